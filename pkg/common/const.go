@@ -41,4 +41,9 @@ const (
 	TagRequired   = "required"
 	TagIgnored    = "ignored"
 	TagDefault    = "default"
+	TagRequiredIn = "required_in"
+	TagFlag       = "flag"
+	TagDesc       = "desc"
+	TagKeep       = "keep"
+	TagScrub      = "scrub"
 )
@@ -0,0 +1,212 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package common
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidMapItem = errors.New("invalid map item, must be in key:value format")
+
+const (
+	// RedactedValue - replacement of sensitive values in effective config and errors...
+	RedactedValue = "[REDACTED]"
+
+	// ValidationRuleRequired - rule of required and required_in tags...
+	ValidationRuleRequired = "required"
+	// ValidationRuleSecretFormat - rule of secret reference string format...
+	ValidationRuleSecretFormat = "secret_format"
+)
+
+// ParseError - error of conversion of string value to type of config field...
+type ParseError struct {
+	Cause error
+	Value string
+	// Type - name of target type, e.g. int64, time.Duration, []string...
+	Type string
+}
+
+func (e *ParseError) Error() string {
+	return "cannot parse value " + strconv.Quote(e.Value) + " as " + e.Type + ": " + e.Cause.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Cause
+}
+
+// redact - replace value in parse error and in message of cause error...
+func (e *ParseError) redact() {
+	var numErr *strconv.NumError
+
+	switch {
+	case errors.As(e.Cause, &numErr):
+		numErr.Num = RedactedValue
+	case e.Value != "" && strings.Contains(e.Cause.Error(), e.Value):
+		e.Cause = &redactedError{
+			cause:   e.Cause,
+			message: strings.ReplaceAll(e.Cause.Error(), e.Value, RedactedValue),
+		}
+	}
+
+	e.Value = RedactedValue
+}
+
+// redactedError - error with redacted message of cause error. Cause is still available
+// by errors.Is and errors.As functions...
+type redactedError struct {
+	cause   error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.cause
+}
+
+// ValidationError - error of config field validation rule, e.g. required value is absent.
+// Cause contains sentinel error, e.g. ErrVariableEmptyButRequired...
+type ValidationError struct {
+	Cause error
+	Rule  string
+}
+
+func (e *ValidationError) Error() string {
+	return "validation rule " + e.Rule + " failed: " + e.Cause.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Cause
+}
+
+// FieldError - error of config field processing with field path and source of value.
+// Value of secret field or field with scrub:"true" tag is always redacted, use NewFieldError function
+// for creation of error...
+type FieldError struct {
+	Cause error
+	// Path - path of struct field, e.g. AppConfig.Database.Port...
+	Path string
+	// EnvKey - name of variable, which was used for value search...
+	EnvKey string
+	// SecretKey - name of secret, which was used for value search...
+	SecretKey string
+	// Source - name of source, which provided the value: env, default, secret, flags, etc...
+	Source string
+	Value  string
+}
+
+func (e *FieldError) Error() string {
+	detailsList := make([]string, 0, 4)
+
+	if e.EnvKey != "" {
+		detailsList = append(detailsList, "env "+e.EnvKey)
+	}
+
+	if e.SecretKey != "" {
+		detailsList = append(detailsList, "secret "+e.SecretKey)
+	}
+
+	if e.Source != "" {
+		detailsList = append(detailsList, "source "+e.Source)
+	}
+
+	if e.Value != "" {
+		detailsList = append(detailsList, "value "+strconv.Quote(e.Value))
+	}
+
+	message := "config field " + e.Path
+	if len(detailsList) != 0 {
+		message += " (" + strings.Join(detailsList, ", ") + ")"
+	}
+
+	return message + ": " + e.Cause.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Cause
+}
+
+// NewFieldError - create error of config field. Value of field and value of parse error in cause
+// will be redacted, if isSecret is true or field has scrub:"true" tag...
+func NewFieldError(cause error, path string, field Field, isSecret bool) *FieldError {
+	fieldErr := &FieldError{
+		Cause:     cause,
+		Path:      path,
+		EnvKey:    field.EnvKey,
+		SecretKey: "",
+		Source:    field.Source,
+		Value:     field.Value,
+	}
+
+	if isSecret {
+		fieldErr.EnvKey = ""
+		fieldErr.SecretKey = field.EnvKey
+	}
+
+	if isSecret && field.SecretKey != "" {
+		fieldErr.EnvKey = field.EnvKey
+		fieldErr.SecretKey = field.SecretKey
+	}
+
+	if !isSecret && !IsScrubField(field.RfTags) {
+		return fieldErr
+	}
+
+	if fieldErr.Value != "" {
+		fieldErr.Value = RedactedValue
+	}
+
+	var parseErr *ParseError
+	if errors.As(cause, &parseErr) {
+		parseErr.redact()
+	}
+
+	return fieldErr
+}
+
+// IsScrubField - check that field has scrub:"true" tag. Invalid tag value treated as true - it's safer to hide value...
+func IsScrubField(tags reflect.StructTag) bool {
+	boolVarSrt, isTagExists := tags.Lookup(TagScrub)
+	if !isTagExists {
+		return false
+	}
+
+	isScrub, err := strconv.ParseBool(boolVarSrt)
+
+	return isScrub || err != nil
+}
@@ -0,0 +1,194 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// LogLevels - levels of config loading log records. Pass LogLevels value to dependencies list of
+// config manager or JSON config service for changing of default levels...
+type LogLevels struct {
+	// Discovery - level of variable sources and config data sources discovery records...
+	Discovery slog.Level
+	// Field - level of resolved field records...
+	Field slog.Level
+	// Default - level of applied default value records...
+	Default slog.Level
+	// Secret - level of fetched secret records...
+	Secret slog.Level
+	// Hook - level of prepare hooks timing records...
+	Hook slog.Level
+	// Scrub - level of process environment scrubbing records...
+	Scrub slog.Level
+}
+
+// DefaultLogLevels returns default levels of config loading log records...
+func DefaultLogLevels() LogLevels {
+	return LogLevels{
+		Discovery: slog.LevelInfo,
+		Field:     slog.LevelDebug,
+		Default:   slog.LevelDebug,
+		Secret:    slog.LevelDebug,
+		Hook:      slog.LevelDebug,
+		Scrub:     slog.LevelInfo,
+	}
+}
+
+// Logger - structured logger of config loading flow. Values of secret fields are always redacted,
+// for secrets only key and provider name are logged. Zero value and nil Logger are no-op loggers...
+type Logger struct {
+	logger *slog.Logger
+	levels LogLevels
+}
+
+// SourceDiscovered - log discovery of variable source or config data source...
+func (l *Logger) SourceDiscovered(sourceName string, order int) {
+	l.logAttrs(l.getLevels().Discovery, "config source discovered",
+		slog.String("source", sourceName), slog.Int("order", order))
+}
+
+// FieldResolved - log resolved config field. Value will be redacted if isSensitive is true...
+func (l *Logger) FieldResolved(path, key, sourceName, value string, isSensitive bool) {
+	if isSensitive {
+		value = RedactedValue
+	}
+
+	l.logAttrs(l.getLevels().Field, "config field resolved",
+		slog.String("path", path), slog.String("key", key),
+		slog.String("source", sourceName), slog.String("value", value))
+}
+
+// DefaultApplied - log usage of default value of config field. Value will be redacted if isSensitive is true...
+func (l *Logger) DefaultApplied(path, key, value string, isSensitive bool) {
+	if isSensitive {
+		value = RedactedValue
+	}
+
+	l.logAttrs(l.getLevels().Default, "config field default value applied",
+		slog.String("path", path), slog.String("key", key), slog.String("value", value))
+}
+
+// SecretFetched - log fetching of secret. Only secret key and provider name are logged...
+func (l *Logger) SecretFetched(path, key string, provider interface{}, isExists bool) {
+	l.logAttrs(l.getLevels().Secret, "config secret fetched",
+		slog.String("path", path), slog.String("key", key),
+		slog.String("provider", ProviderName(provider)), slog.Bool("exists", isExists))
+}
+
+// HookFinished - log duration and result of prepare hook call, e.g. Prepare, PrepareWith, InitWith...
+func (l *Logger) HookFinished(path, hookName string, duration time.Duration, err error) {
+	attrsList := []slog.Attr{
+		slog.String("path", path),
+		slog.String("hook", hookName),
+		slog.Duration("duration", duration),
+	}
+
+	if err != nil {
+		attrsList = append(attrsList, slog.String("error", err.Error()))
+	}
+
+	l.logAttrs(l.getLevels().Hook, "config prepare hook finished", attrsList...)
+}
+
+// VariableScrubbed - log result of process environment scrubbing of variable...
+func (l *Logger) VariableScrubbed(key string, isRemoved bool) {
+	l.logAttrs(l.getLevels().Scrub, "config environment variable scrubbing",
+		slog.String("key", key), slog.Bool("removed", isRemoved))
+}
+
+func (l *Logger) getLevels() LogLevels {
+	if l == nil {
+		return DefaultLogLevels()
+	}
+
+	return l.levels
+}
+
+func (l *Logger) logAttrs(level slog.Level, message string, attrsList ...slog.Attr) {
+	if l == nil || l.logger == nil {
+		return
+	}
+
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	l.logger.LogAttrs(ctx, level, message, attrsList...)
+}
+
+// ProviderName returns name of secret provider or variable source - result of GetSourceName function
+// or name of provider type...
+func ProviderName(provider interface{}) string {
+	castedNameProvider, isPossibleToCast := provider.(interface{ GetSourceName() string })
+	if isPossibleToCast {
+		return castedNameProvider.GetSourceName()
+	}
+
+	return fmt.Sprintf("%T", provider)
+}
+
+// NewLogger - create config loading logger. Logger will be no-op logger, if nil logger passed...
+func NewLogger(logger *slog.Logger, levels LogLevels) *Logger {
+	return &Logger{
+		logger: logger,
+		levels: levels,
+	}
+}
+
+// NewLoggerByDependencies - create config loading logger by *slog.Logger and LogLevels values
+// from dependencies list...
+func NewLoggerByDependencies(dependenciesList []interface{}) *Logger {
+	var logger *slog.Logger
+
+	levels := DefaultLogLevels()
+
+	for _, dependency := range dependenciesList {
+		switch castedDependency := dependency.(type) {
+		case *slog.Logger:
+			logger = castedDependency
+		case LogLevels:
+			levels = castedDependency
+		case *LogLevels:
+			levels = *castedDependency
+		default:
+			continue
+		}
+	}
+
+	return NewLogger(logger, levels)
+}
@@ -0,0 +1,315 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package common
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
+)
+
+var (
+	ErrInvalidSemVer            = errors.New("invalid semantic version")
+	ErrVersionLowerThanMinimum  = errors.New("version is lower than minimal supported version")
+	ErrVersionHigherThanMaximum = errors.New("version is higher than maximal supported version")
+	// ErrReleaseVersionIsNotDefined - release version of application binary is not available for
+	// version constraint check, e.g. ldflag manager is not passed to dependencies list...
+	ErrReleaseVersionIsNotDefined = errors.New("release version of application is not defined")
+)
+
+const semVerCorePartsCount = 3
+
+// SemVer - semantic version, see https://semver.org/spec/v2.0.0.html
+type SemVer struct {
+	Prerelease []string
+	Build      []string
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+}
+
+// ParseSemVer - parse semantic version string with optional "v" prefix, e.g. v1.2.3-rc.1+build.42...
+func ParseSemVer(version string) (*SemVer, error) {
+	rawVersion := strings.TrimPrefix(version, "v")
+
+	rawVersion, build, hasBuild := strings.Cut(rawVersion, "+")
+	rawVersion, prerelease, hasPrerelease := strings.Cut(rawVersion, "-")
+
+	coreParts := strings.Split(rawVersion, ".")
+	if len(coreParts) != semVerCorePartsCount {
+		return nil, errfmt.ErrorOnly(ErrInvalidSemVer, version)
+	}
+
+	result := &SemVer{
+		Prerelease: nil,
+		Build:      nil,
+		Major:      0,
+		Minor:      0,
+		Patch:      0,
+	}
+
+	coreValues := [semVerCorePartsCount]*uint64{&result.Major, &result.Minor, &result.Patch}
+	for i, part := range coreParts {
+		if !isSemVerNumericIdentifier(part) {
+			return nil, errfmt.ErrorOnly(ErrInvalidSemVer, version)
+		}
+
+		value, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, errfmt.ErrorOnly(ErrInvalidSemVer, version)
+		}
+
+		*coreValues[i] = value
+	}
+
+	if hasPrerelease {
+		result.Prerelease = strings.Split(prerelease, ".")
+		for _, identifier := range result.Prerelease {
+			if !isSemVerIdentifier(identifier) {
+				return nil, errfmt.ErrorOnly(ErrInvalidSemVer, version)
+			}
+
+			if isSemVerDigits(identifier) && !isSemVerNumericIdentifier(identifier) {
+				return nil, errfmt.ErrorOnly(ErrInvalidSemVer, version)
+			}
+		}
+	}
+
+	if hasBuild {
+		result.Build = strings.Split(build, ".")
+		for _, identifier := range result.Build {
+			if !isSemVerIdentifier(identifier) {
+				return nil, errfmt.ErrorOnly(ErrInvalidSemVer, version)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// MustParseSemVer - same as ParseSemVer, but panics in case of invalid version...
+func MustParseSemVer(version string) *SemVer {
+	result, err := ParseSemVer(version)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// String returns semantic version string without "v" prefix...
+func (v *SemVer) String() string {
+	builder := strings.Builder{}
+
+	builder.WriteString(strconv.FormatUint(v.Major, 10))
+	builder.WriteString(".")
+	builder.WriteString(strconv.FormatUint(v.Minor, 10))
+	builder.WriteString(".")
+	builder.WriteString(strconv.FormatUint(v.Patch, 10))
+
+	if len(v.Prerelease) != 0 {
+		builder.WriteString("-" + strings.Join(v.Prerelease, "."))
+	}
+
+	if len(v.Build) != 0 {
+		builder.WriteString("+" + strings.Join(v.Build, "."))
+	}
+
+	return builder.String()
+}
+
+// IsPrerelease - check that version has prerelease identifiers...
+func (v *SemVer) IsPrerelease() bool {
+	return len(v.Prerelease) != 0
+}
+
+// Compare versions by precedence rules of SemVer 2.0. Build metadata is ignored.
+// Returns -1 if v < other, 0 if v == other and 1 if v > other...
+func (v *SemVer) Compare(other *SemVer) int {
+	coreList := [semVerCorePartsCount][2]uint64{
+		{v.Major, other.Major},
+		{v.Minor, other.Minor},
+		{v.Patch, other.Patch},
+	}
+
+	for _, pair := range coreList {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+func (v *SemVer) LessThan(other *SemVer) bool {
+	return v.Compare(other) < 0
+}
+
+func (v *SemVer) GreaterThan(other *SemVer) bool {
+	return v.Compare(other) > 0
+}
+
+func (v *SemVer) Equal(other *SemVer) bool {
+	return v.Compare(other) == 0
+}
+
+// CheckVersionConstraint - check that version is in range of minimal and maximal versions, both inclusive.
+// Empty minVersion or maxVersion means no limit...
+func CheckVersionConstraint(version *SemVer, minVersion, maxVersion string) error {
+	if minVersion != "" {
+		minSemVer, err := ParseSemVer(minVersion)
+		if err != nil {
+			return errfmt.ErrorNoWrap(err)
+		}
+
+		if version.LessThan(minSemVer) {
+			return errfmt.ErrorOnly(ErrVersionLowerThanMinimum, version.String()+" < "+minSemVer.String())
+		}
+	}
+
+	if maxVersion != "" {
+		maxSemVer, err := ParseSemVer(maxVersion)
+		if err != nil {
+			return errfmt.ErrorNoWrap(err)
+		}
+
+		if version.GreaterThan(maxSemVer) {
+			return errfmt.ErrorOnly(ErrVersionHigherThanMaximum, version.String()+" > "+maxSemVer.String())
+		}
+	}
+
+	return nil
+}
+
+func comparePrerelease(left, right []string) int {
+	// version without prerelease has higher precedence
+	switch {
+	case len(left) == 0 && len(right) == 0:
+		return 0
+	case len(left) == 0:
+		return 1
+	case len(right) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(left) && i < len(right); i++ {
+		result := comparePrereleaseIdentifier(left[i], right[i])
+		if result != 0 {
+			return result
+		}
+	}
+
+	switch {
+	case len(left) < len(right):
+		return -1
+	case len(left) > len(right):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func comparePrereleaseIdentifier(left, right string) int {
+	isLeftNumeric := isSemVerDigits(left)
+	isRightNumeric := isSemVerDigits(right)
+
+	switch {
+	case isLeftNumeric && isRightNumeric:
+		leftValue, _ := strconv.ParseUint(left, 10, 64)
+		rightValue, _ := strconv.ParseUint(right, 10, 64)
+
+		switch {
+		case leftValue < rightValue:
+			return -1
+		case leftValue > rightValue:
+			return 1
+		default:
+			return 0
+		}
+	// numeric identifiers always have lower precedence than alphanumeric identifiers
+	case isLeftNumeric:
+		return -1
+	case isRightNumeric:
+		return 1
+	default:
+		return strings.Compare(left, right)
+	}
+}
+
+func isSemVerDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, symbol := range value {
+		if symbol < '0' || symbol > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isSemVerNumericIdentifier - digits without leading zeroes...
+func isSemVerNumericIdentifier(value string) bool {
+	if !isSemVerDigits(value) {
+		return false
+	}
+
+	return value == "0" || value[0] != '0'
+}
+
+// isSemVerIdentifier - non-empty [0-9A-Za-z-] string...
+func isSemVerIdentifier(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, symbol := range value {
+		isAllowed := (symbol >= '0' && symbol <= '9') ||
+			(symbol >= 'a' && symbol <= 'z') ||
+			(symbol >= 'A' && symbol <= 'Z') ||
+			symbol == '-'
+		if !isAllowed {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,167 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package common
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
+)
+
+// LookupDefault - search default value of field for passed environment name.
+// Search order:
+//   - environment-specific tag, e.g. default_production:"30s" or default_prod:"30s" for alias of environment.
+//     Tag of canonical environment name has priority over tags of aliases, aliases are checked in sorted order
+//   - environment-specific list in default tag, e.g. default:"dev=5s,production=30s"
+//   - plain default tag value
+//
+// environmentNames is a map of environment names and aliases to canonical environment names.
+// Default tag is treated as environment-specific list only if each comma-separated item starts with
+// known environment name or alias and "=" symbol. If environment-specific list doesn't contain passed
+// environment - field has no default value. For list values with commas use default_<env> tags...
+func LookupDefault(tags reflect.StructTag,
+	environment string,
+	environmentNames map[string]string,
+) (string, bool) {
+	canonicalEnvName := environmentNames[environment]
+
+	if canonicalEnvName != "" {
+		for _, envName := range environmentNamesOf(canonicalEnvName, environmentNames) {
+			value, isExists := tags.Lookup(TagDefault + "_" + envName)
+			if isExists {
+				return value, true
+			}
+		}
+	}
+
+	defaultValue, hasDefaultValue := tags.Lookup(TagDefault)
+	if !hasDefaultValue {
+		return "", false
+	}
+
+	envValues, isEnvSpecific := parseEnvSpecificList(defaultValue, environmentNames)
+	if !isEnvSpecific {
+		return defaultValue, true
+	}
+
+	value, isExists := envValues[canonicalEnvName]
+
+	return value, isExists
+}
+
+// IsRequired - check required and required_in tags of field for passed environment name.
+// Tag required_in contains comma-separated list of environment names or aliases, e.g. required_in:"production,staging"...
+func IsRequired(tags reflect.StructTag,
+	environment string,
+	environmentNames map[string]string,
+) (bool, error) {
+	boolVarSrt, isTagExists := tags.Lookup(TagRequired)
+	if isTagExists {
+		boolVar, err := strconv.ParseBool(boolVarSrt)
+		if err != nil {
+			return false, errfmt.ErrorNoWrap(err)
+		}
+
+		if boolVar {
+			return true, nil
+		}
+	}
+
+	requiredIn, isTagExists := tags.Lookup(TagRequiredIn)
+	if !isTagExists {
+		return false, nil
+	}
+
+	canonicalEnvName := environmentNames[environment]
+	if canonicalEnvName == "" {
+		return false, nil
+	}
+
+	for _, envName := range strings.Split(requiredIn, ",") {
+		if environmentNames[strings.TrimSpace(envName)] == canonicalEnvName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// LookupSecretName - name of secret in secret provider - value of secret_name tag,
+// or envconfig key if tag is not defined...
+func LookupSecretName(tags reflect.StructTag, envKey string) string {
+	secretName := tags.Get(TagSecretName)
+	if secretName == "" {
+		return envKey
+	}
+
+	return secretName
+}
+
+// environmentNamesOf returns canonical environment name and sorted list of its aliases...
+func environmentNamesOf(canonicalEnvName string, environmentNames map[string]string) []string {
+	aliasesList := make([]string, 0)
+
+	for envName, canonicalName := range environmentNames {
+		if canonicalName == canonicalEnvName && envName != canonicalEnvName {
+			aliasesList = append(aliasesList, envName)
+		}
+	}
+
+	sort.Strings(aliasesList)
+
+	return append([]string{canonicalEnvName}, aliasesList...)
+}
+
+func parseEnvSpecificList(value string, environmentNames map[string]string) (map[string]string, bool) {
+	items := strings.Split(value, ",")
+	result := make(map[string]string, len(items))
+
+	for _, item := range items {
+		envName, envValue, isFound := strings.Cut(item, "=")
+		if !isFound {
+			return nil, false
+		}
+
+		canonicalName, isKnown := environmentNames[strings.TrimSpace(envName)]
+		if !isKnown {
+			return nil, false
+		}
+
+		result[canonicalName] = envValue
+	}
+
+	return result, true
+}
@@ -36,7 +36,13 @@ import "reflect"
 
 // Field maintains information about the struct field...
 type Field struct {
-	Name    string
+	Name string
+	// EnvKey - name of variable, which was used for value search...
+	EnvKey string
+	// SecretKey - name of secret in secret provider, empty for non-secret fields...
+	SecretKey string
+	// Source - name of source, which provided the value: env, default, secret, flags, etc...
+	Source  string
 	RfValue reflect.Value
 	RfTags  reflect.StructTag
 	Value   string
@@ -33,6 +33,7 @@
 package common
 
 import (
+	"encoding"
 	"reflect"
 	"strconv"
 	"strings"
@@ -41,7 +42,28 @@ import (
 	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
 )
 
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// IsTextUnmarshaler - check that pointer to type implements encoding.TextUnmarshaler. Values of such types,
+// including structs, are parsed by UnmarshalText function as single value...
+func IsTextUnmarshaler(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return reflect.PointerTo(typ).Implements(textUnmarshalerType)
+}
+
+func newParseError(cause error, value string, typ reflect.Type) *ParseError {
+	return &ParseError{
+		Cause: cause,
+		Value: value,
+		Type:  typ.String(),
+	}
+}
+
 // SetField - function for case value in struct by field name and reflect value...
+// Values of types, which implement encoding.TextUnmarshaler, e.g. ByteSize of config package, parsed by UnmarshalText...
 // TODO: refactor it - separate by sub-function and move to separated service-component...
 //
 //nolint:funlen,gocognit,cyclop // it's ok. Need to refactor this function, but now - it's ok.
@@ -57,6 +79,16 @@ func SetField(value string, field reflect.Value) error {
 		field = field.Elem()
 	}
 
+	if field.CanAddr() && IsTextUnmarshaler(typ) {
+		//nolint:forcetypeassert // it's ok, type checked by IsTextUnmarshaler
+		err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+		if err != nil {
+			return errfmt.ErrorNoWrap(newParseError(err, value, typ))
+		}
+
+		return nil
+	}
+
 	switch typ.Kind() {
 	case reflect.String:
 		field.SetString(value)
@@ -76,21 +108,21 @@ func SetField(value string, field reflect.Value) error {
 		}
 
 		if err != nil {
-			return errfmt.ErrorNoWrap(err)
+			return errfmt.ErrorNoWrap(newParseError(err, value, typ))
 		}
 
 		field.SetInt(val)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		val, err := strconv.ParseUint(value, 0, typ.Bits())
 		if err != nil {
-			return errfmt.ErrorNoWrap(err)
+			return errfmt.ErrorNoWrap(newParseError(err, value, typ))
 		}
 
 		field.SetUint(val)
 	case reflect.Bool:
 		val, err := strconv.ParseBool(value)
 		if err != nil {
-			return errfmt.ErrorNoWrap(err)
+			return errfmt.ErrorNoWrap(newParseError(err, value, typ))
 		}
 
 		field.SetBool(val)
@@ -98,7 +130,7 @@ func SetField(value string, field reflect.Value) error {
 	case reflect.Float32, reflect.Float64:
 		val, err := strconv.ParseFloat(value, typ.Bits())
 		if err != nil {
-			return errfmt.ErrorNoWrap(err)
+			return errfmt.ErrorNoWrap(newParseError(err, value, typ))
 		}
 
 		field.SetFloat(val)
@@ -129,7 +161,7 @@ func SetField(value string, field reflect.Value) error {
 			for _, pair := range pairs {
 				kvpair := strings.Split(pair, ":")
 				if len(kvpair) != 2 {
-					return errfmt.NewErrorf("invalid map item: %q", pair)
+					return errfmt.ErrorNoWrap(newParseError(ErrInvalidMapItem, pair, typ))
 				}
 
 				pairKey := reflect.New(typ.Key()).Elem()
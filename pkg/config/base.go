@@ -35,6 +35,9 @@ package config
 import (
 	"os"
 	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
 )
 
 const (
@@ -53,6 +56,8 @@ var (
 type BaseConfig struct {
 	ldFlagManagerSrv ldFlagManagerService
 	e                errorFormatterService
+	envRegistrySvc   environmentRegistryService
+	environment      *EnvironmentDefinition
 
 	Environment      string `envconfig:"APP_ENV" default:"development"`
 	StageName        string `envconfig:"APP_STAGE" default:"dev"`
@@ -65,11 +70,24 @@ type BaseConfig struct {
 
 // Prepare variables to static configuration...
 func (c *BaseConfig) Prepare() error {
+	c.e = errFormatterOrDefault(c.e)
+
 	host, err := os.Hostname()
 	if err != nil {
 		return c.e.ErrorOnly(err)
 	}
 
+	envRegistrySvc := c.envRegistrySvc
+	if envRegistrySvc == nil {
+		envRegistrySvc = NewDefaultEnvironmentRegistry()
+	}
+
+	environment, isRegistered := envRegistrySvc.Lookup(c.Environment)
+	if !isRegistered {
+		return c.e.ErrorOnly(ErrEnvironmentIsNotRegistered, c.Environment)
+	}
+
+	c.environment = environment
 	c.hostname = host
 	c.applicationPID = os.Getpid()
 
@@ -83,6 +101,8 @@ func (c *BaseConfig) PrepareWith(cfgSrvList ...interface{}) error {
 			c.ldFlagManagerSrv = castedCfgDep
 		case errorFormatterService:
 			c.e = castedCfgDep
+		case environmentRegistryService:
+			c.envRegistrySvc = castedCfgDep
 		default:
 			continue
 		}
@@ -101,32 +121,77 @@ func (c *BaseConfig) GetEnvironmentName() string {
 	return c.Environment
 }
 
-// IsProd ...
+// GetEnvironment returns definition of application environment. Will be filled on Prepare call...
+func (c *BaseConfig) GetEnvironment() *EnvironmentDefinition {
+	return c.environment
+}
+
+// HasEnvironmentTrait - check that application environment has all passed traits...
+func (c *BaseConfig) HasEnvironmentTrait(trait EnvironmentTrait) bool {
+	if c.environment == nil {
+		return false
+	}
+
+	return c.environment.HasTrait(trait)
+}
+
+// IsProductionLike - check that application environment serves real users or mirrors production setup...
+func (c *BaseConfig) IsProductionLike() bool {
+	return c.HasEnvironmentTrait(EnvTraitProductionLike)
+}
+
+// IsDebugAllowed - check that debug mode can be enabled in application environment...
+func (c *BaseConfig) IsDebugAllowed() bool {
+	return c.HasEnvironmentTrait(EnvTraitAllowsDebug)
+}
+
+// IsTLSRequired - check that all connections of application must use TLS...
+func (c *BaseConfig) IsTLSRequired() bool {
+	return c.HasEnvironmentTrait(EnvTraitRequiresTLS)
+}
+
+// IsProd - compare APP_ENV value with production environment name. Aliases of environment are not supported...
+//
+// Deprecated: use IsProductionLike or HasEnvironmentTrait instead.
 func (c *BaseConfig) IsProd() bool {
 	return c.Environment == EnvProduction
 }
 
-// IsStage ...
+// IsStage - compare APP_ENV value with staging environment name. Aliases of environment are not supported...
+//
+// Deprecated: use HasEnvironmentTrait instead.
 func (c *BaseConfig) IsStage() bool {
 	return c.Environment == EnvStaging
 }
 
-// IsTest ...
+// IsTest - compare APP_ENV value with staging or testing environment names...
+//
+// Deprecated: use HasEnvironmentTrait(EnvTraitTesting) instead.
 func (c *BaseConfig) IsTest() bool {
 	return c.Environment == EnvStaging || c.Environment == EnvTesting
 }
 
-// IsDev ...
+// IsDev - compare APP_ENV value with local or development environment names...
+//
+// Deprecated: use HasEnvironmentTrait(EnvTraitDevelopment) instead.
 func (c *BaseConfig) IsDev() bool {
 	return c.Environment == EnvLocal || c.Environment == EnvDev
 }
 
-// IsDebug ...
+// IsDebug returns APP_DEBUG value. Environment traits are not checked, use IsDebugEnabled function
+// for debug mode, which is allowed by environment...
 func (c *BaseConfig) IsDebug() bool {
 	return c.Debug
 }
 
-// IsLocal ...
+// IsDebugEnabled - check that debug mode enabled and allowed in application environment...
+func (c *BaseConfig) IsDebugEnabled() bool {
+	return c.Debug && c.IsDebugAllowed()
+}
+
+// IsLocal - compare APP_ENV value with local environment name...
+//
+// Deprecated: use HasEnvironmentTrait(EnvTraitLocal) instead.
 func (c *BaseConfig) IsLocal() bool {
 	return c.Environment == EnvLocal
 }
@@ -159,6 +224,15 @@ func (c *BaseConfig) GetReleaseTag() string {
 	return c.ldFlagManagerSrv.GetReleaseTag()
 }
 
+// GetReleaseVersion returns release tag parsed as semantic version...
+func (c *BaseConfig) GetReleaseVersion() (*common.SemVer, error) {
+	if c.ldFlagManagerSrv == nil {
+		return nil, ErrReleaseVersionIsNotDefined
+	}
+
+	return common.ParseSemVer(c.ldFlagManagerSrv.GetReleaseTag())
+}
+
 func (c *BaseConfig) GetCommitID() string {
 	return c.ldFlagManagerSrv.GetCommitID()
 }
@@ -179,6 +253,30 @@ func (c *BaseConfig) GetBuildDate() time.Time {
 	return c.ldFlagManagerSrv.GetBuildDate()
 }
 
+// GetBuildInfo returns build metadata of application binary.
+// Runtime/debug build info fields will be empty if ldflag manager doesn't provide them...
+func (c *BaseConfig) GetBuildInfo() *BuildInfo {
+	castedBuildInfoSvc, isPossibleToCast := c.ldFlagManagerSrv.(buildInfoProviderService)
+	if isPossibleToCast {
+		return castedBuildInfoSvc.GetBuildInfo()
+	}
+
+	return &BuildInfo{
+		ReleaseTag:    c.ldFlagManagerSrv.GetReleaseTag(),
+		CommitID:      c.ldFlagManagerSrv.GetCommitID(),
+		ShortCommitID: c.ldFlagManagerSrv.GetShortCommitID(),
+		BuildNumber:   c.ldFlagManagerSrv.GetBuildNumber(),
+		BuildDate:     c.ldFlagManagerSrv.GetBuildDate(),
+		VCSTime:       time.Time{},
+		VCSType:       "",
+		VCSModified:   false,
+		GoVersion:     "",
+		ModulePath:    "",
+		ModuleVersion: "",
+		Dependencies:  nil,
+	}
+}
+
 func NewBaseConfig(applicationName string) *BaseConfig {
 	return &BaseConfig{
 		Environment:      "",    // will be filled in config filling stage by config service
@@ -188,7 +286,9 @@ func NewBaseConfig(applicationName string) *BaseConfig {
 		hostname:         "",    // will be filled in config filling stage by config service on call Prepare function
 		applicationName:  applicationName,
 		applicationPID:   0, // will be filled in config filling stage by config service on call Prepare function
-		ldFlagManagerSrv: newDefaultLdFlagManager(),
-		e:                nil, // will be filled in config filling stage by config service on call PrepareWith function
+		ldFlagManagerSrv: DefaultLdFlagsManager(),
+		e:                errfmt.NewStdFormatter(), // can be replaced by config service on call PrepareWith function
+		envRegistrySvc:   nil,                      // default registry will be used, if registry not passed to PrepareWith function
+		environment:      nil,                      // will be filled in config filling stage by config service on call Prepare function
 	}
 }
@@ -0,0 +1,221 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+const (
+	buildInfoDevelVersion   = "(devel)"
+	buildInfoVCSKey         = "vcs"
+	buildInfoVCSRevisionKey = "vcs.revision"
+	buildInfoVCSTimeKey     = "vcs.time"
+	buildInfoVCSModifiedKey = "vcs.modified"
+)
+
+// BuildDependency - information about module dependency of application binary...
+type BuildDependency struct {
+	Path    string
+	Version string
+	Sum     string
+	// Replace - replacement of dependency module, nil if module is not replaced...
+	Replace *BuildDependency
+}
+
+// BuildInfo - build metadata of application binary - ldflags values merged with runtime/debug build info...
+type BuildInfo struct {
+	BuildDate     time.Time
+	VCSTime       time.Time
+	ReleaseTag    string
+	CommitID      string
+	ShortCommitID string
+	VCSType       string
+	GoVersion     string
+	ModulePath    string
+	ModuleVersion string
+	Dependencies  []BuildDependency
+	BuildNumber   uint64
+	VCSModified   bool
+}
+
+// fillByBuildInfo - fill build metadata by runtime/debug build info.
+// Release tag, commit IDs and build date will be replaced only if they contain stub values...
+func (m *ldFlagManager) fillByBuildInfo(buildInfo *debug.BuildInfo) {
+	if buildInfo == nil {
+		return
+	}
+
+	m.goVersion = buildInfo.GoVersion
+	m.modulePath = buildInfo.Main.Path
+	m.moduleVersion = buildInfo.Main.Version
+
+	m.dependencies = make([]BuildDependency, 0, len(buildInfo.Deps))
+	for _, dep := range buildInfo.Deps {
+		m.dependencies = append(m.dependencies, newBuildDependency(dep))
+	}
+
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case buildInfoVCSKey:
+			m.vcsType = setting.Value
+		case buildInfoVCSRevisionKey:
+			// revisions of non-git VCS are not supported by commit ID validation
+			if !isValidCommitID(setting.Value) {
+				continue
+			}
+
+			if m.commitID == ldFlagDefaultCommit {
+				m.commitID = setting.Value
+				m.isVCSCommitID = true
+			}
+
+			if m.shortCommitID == ldFlagDefaultShortCommit {
+				m.shortCommitID = setting.Value[:len(ldFlagDefaultShortCommit)]
+			}
+		case buildInfoVCSTimeKey:
+			vcsTime, err := time.Parse(time.RFC3339, setting.Value)
+			if err != nil {
+				continue
+			}
+
+			m.vcsTime = vcsTime
+
+			if m.buildDateAt.Unix() == 0 {
+				m.buildDateAt = vcsTime
+			}
+		case buildInfoVCSModifiedKey:
+			m.vcsModified, _ = strconv.ParseBool(setting.Value)
+		default:
+			continue
+		}
+	}
+
+	if m.releaseTag == ldFlagDefaultReleaseTag &&
+		m.moduleVersion != "" && m.moduleVersion != buildInfoDevelVersion {
+		m.releaseTag = m.moduleVersion
+	}
+}
+
+func newBuildDependency(module *debug.Module) BuildDependency {
+	dependency := BuildDependency{
+		Path:    module.Path,
+		Version: module.Version,
+		Sum:     module.Sum,
+		Replace: nil,
+	}
+
+	if module.Replace != nil {
+		replace := newBuildDependency(module.Replace)
+		dependency.Replace = &replace
+	}
+
+	return dependency
+}
+
+// NewLdFlagsManagerWithBuildInfo - create ldflag manager with values from ldflags, merged with
+// runtime/debug build info. Empty ldflags values will be filled by build info:
+//   - release tag - by main module version
+//   - commit ID and short commit ID - by vcs.revision, only if both values are empty.
+//     Empty short commit ID with passed commit ID is filled by prefix of commit ID
+//   - build date - by vcs.time
+//
+// Also build info provides go version, vcs.modified flag and list of dependencies...
+func NewLdFlagsManagerWithBuildInfo(
+	errFmtSvc errorFormatterService,
+	releaseTag,
+	commitID,
+	shortCommitID,
+	buildNumber,
+	buildDateTS string,
+) (*ldFlagManager, error) {
+	buildInfo, _ := debug.ReadBuildInfo()
+
+	return newLdFlagsManagerWithBuildInfo(errFormatterOrDefault(errFmtSvc), buildInfo,
+		releaseTag, commitID, shortCommitID, buildNumber, buildDateTS)
+}
+
+func newLdFlagsManagerWithBuildInfo(
+	errFmtSvc errorFormatterService,
+	buildInfo *debug.BuildInfo,
+	releaseTag,
+	commitID,
+	shortCommitID,
+	buildNumber,
+	buildDateTS string,
+) (*ldFlagManager, error) {
+	manager := newStubLdFlagManager()
+	manager.fillByBuildInfo(buildInfo)
+
+	if releaseTag != "" {
+		manager.releaseTag = releaseTag
+	}
+
+	// short commit ID of ldflags commit ID must not be taken from build info - they can name different commits
+	if commitID != "" {
+		manager.commitID = commitID
+		manager.isVCSCommitID = false
+		manager.shortCommitID = commitID[:min(len(commitID), len(ldFlagDefaultShortCommit))]
+	}
+
+	if shortCommitID != "" {
+		manager.shortCommitID = shortCommitID
+	}
+
+	if buildNumber != "" {
+		buildNumberRaw, err := strconv.ParseUint(buildNumber, 10, 0)
+		if err != nil {
+			return nil, errFmtSvc.ErrorOnly(err)
+		}
+
+		manager.buildNumber = buildNumberRaw
+	}
+
+	if buildDateTS != "" {
+		buildDateTSRaw, err := strconv.ParseInt(buildDateTS, 10, 64)
+		if err != nil {
+			return nil, errFmtSvc.ErrorOnly(err, buildDateTS)
+		}
+
+		manager.buildDateAt = time.Unix(buildDateTSRaw, 0)
+	}
+
+	err := manager.validate()
+	if err != nil {
+		return nil, errFmtSvc.ErrorOnly(err, manager.releaseTag, manager.commitID, manager.shortCommitID)
+	}
+
+	return manager, nil
+}
@@ -0,0 +1,159 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+func TestLdFlagsManagerWithBuildInfo(t *testing.T) {
+	const (
+		vcsRevision = "4c3452b1a5d6e7f8091a2b3c4d5e6f708192a3b4"
+		vcsTime     = "2024-10-09T10:00:00Z"
+	)
+
+	buildInfo := &debug.BuildInfo{
+		GoVersion: "go1.22.5",
+		Path:      "github.com/crypto-bundle/bc-wallet-tron-hdwallet/cmd/api",
+		Main: debug.Module{
+			Path:    "github.com/crypto-bundle/bc-wallet-tron-hdwallet",
+			Version: "v1.2.3",
+			Sum:     "",
+			Replace: nil,
+		},
+		Deps: []*debug.Module{
+			{
+				Path:    "github.com/joho/godotenv",
+				Version: "v1.5.1",
+				Sum:     "h1:7eLL/+HRGLY0ldzfGMeQkb7vMd0as4CfYvUVzLqw0N0=",
+				Replace: &debug.Module{Path: "../godotenv", Version: "", Sum: "", Replace: nil},
+			},
+		},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: vcsRevision},
+			{Key: "vcs.time", Value: vcsTime},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	manager, err := newLdFlagsManagerWithBuildInfo(common.NewMockErrFormatter(), buildInfo,
+		"", "", "", "42", "")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	expectedBuildDate, _ := time.Parse(time.RFC3339, vcsTime)
+
+	info := (&BaseConfig{ldFlagManagerSrv: manager}).GetBuildInfo()
+
+	if info.ReleaseTag != "v1.2.3" {
+		t.Errorf("not equal ReleaseTag")
+	}
+
+	if info.CommitID != vcsRevision || info.ShortCommitID != vcsRevision[:8] {
+		t.Errorf("not equal CommitID or ShortCommitID")
+	}
+
+	if info.BuildNumber != 42 {
+		t.Errorf("not equal BuildNumber")
+	}
+
+	if !info.BuildDate.Equal(expectedBuildDate) || manager.GetBuildDateTS() != expectedBuildDate.Unix() {
+		t.Errorf("not equal BuildDate")
+	}
+
+	if !info.VCSModified || info.VCSType != "git" || info.GoVersion != "go1.22.5" {
+		t.Errorf("not equal VCS info")
+	}
+
+	if len(info.Dependencies) != 1 || info.Dependencies[0].Replace == nil {
+		t.Errorf("not equal Dependencies")
+	}
+
+	manager, err = newLdFlagsManagerWithBuildInfo(common.NewMockErrFormatter(), buildInfo,
+		"v2.0.0", "0000000000000000000000000000000000000001", "00000000", "1", "1728468000")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if manager.GetReleaseTag() != "v2.0.0" || manager.GetShortCommitID() != "00000000" {
+		t.Errorf("ldflags values must have higher priority than build info")
+	}
+
+	if manager.GetBuildDateTS() != 1728468000 {
+		t.Errorf("not equal ldflags BuildDateTS")
+	}
+
+	manager, err = newLdFlagsManagerWithBuildInfo(common.NewMockErrFormatter(), buildInfo,
+		"", "0123456789abcdef0123456789abcdef01234567", "", "1", "")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if manager.GetCommitID() != "0123456789abcdef0123456789abcdef01234567" ||
+		manager.GetShortCommitID() != "01234567" {
+		t.Errorf("short commit ID must be derived from ldflags commit ID: %s", manager.GetShortCommitID())
+	}
+
+	// short commit ID from ldflags is checked only against commit ID from ldflags
+	manager, err = newLdFlagsManagerWithBuildInfo(common.NewMockErrFormatter(), buildInfo,
+		"", "", "fedcba98", "1", "")
+	if err != nil {
+		t.Errorf("short commit ID must not be validated by commit ID of build info: %s", err)
+		return
+	}
+
+	if manager.GetCommitID() != vcsRevision || manager.GetShortCommitID() != "fedcba98" {
+		t.Errorf("not equal CommitID or ShortCommitID")
+	}
+
+	_, err = newLdFlagsManagerWithBuildInfo(common.NewMockErrFormatter(), buildInfo,
+		"", "0123456789abcdef0123456789abcdef01234567", "fedcba98", "1", "")
+	if err == nil {
+		t.Errorf("expected error for short commit ID, which is not a prefix of ldflags commit ID")
+	}
+
+	_, err = newLdFlagsManagerWithBuildInfo(common.NewMockErrFormatter(), buildInfo,
+		"", "", "not-a-commit", "1", "")
+	if err == nil {
+		t.Errorf("expected error for short commit ID with non-hex characters")
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+// ClearENVPolicy - manager-level policy of process environment scrubbing after config preparation.
+// Field tags have higher priority than policy: variable of field with keep:"true" tag will never be removed,
+// variable of field with scrub:"true" tag will always be removed...
+type ClearENVPolicy uint8
+
+const (
+	// ClearENVPolicySecrets - remove variables of secret-tagged fields. Default policy...
+	ClearENVPolicySecrets ClearENVPolicy = iota
+	// ClearENVPolicyAll - remove all variables, which values were read from process environment,
+	// and variables of secret-tagged fields. Variables of fields, which values were read from other sources,
+	// e.g. flags or ConfigMap directory, are kept...
+	ClearENVPolicyAll
+	// ClearENVPolicyNone - remove only variables of fields with scrub:"true" tag...
+	ClearENVPolicyNone
+)
+
+// ClearENVReport - report of process environment scrubbing...
+type ClearENVReport struct {
+	// RemovedKeys - list of removed environment variables...
+	RemovedKeys []string
+	// KeptKeys - list of environment variables, which were read but kept by policy or keep tag...
+	KeptKeys []string
+}
+
+// isFieldVariableMustBeRemoved - decide about removing of field variable from process environment...
+func isFieldVariableMustBeRemoved(policy ClearENVPolicy, field common.Field) (bool, error) {
+	isKeep, err := lookupBoolTag(field, common.TagKeep)
+	if err != nil {
+		return false, err
+	}
+
+	if isKeep {
+		return false, nil
+	}
+
+	isScrub, err := lookupBoolTag(field, common.TagScrub)
+	if err != nil {
+		return false, err
+	}
+
+	if isScrub {
+		return true, nil
+	}
+
+	switch policy {
+	case ClearENVPolicyAll:
+		return field.Source == envSourceName || field.Source == secretSourceName, nil
+	case ClearENVPolicySecrets:
+		return field.Source == secretSourceName, nil
+	case ClearENVPolicyNone:
+		return false, nil
+	default:
+		return false, nil
+	}
+}
+
+func lookupBoolTag(field common.Field, tagName string) (bool, error) {
+	boolVarSrt, isTagExists := field.RfTags.Lookup(tagName)
+	if !isTagExists {
+		return false, nil
+	}
+
+	//nolint:wrapcheck // it's ok, error will be wrapped by caller
+	return strconv.ParseBool(boolVarSrt)
+}
+
+// ClearENV - remove variables of processed fields from process environment according to policy and fields tags.
+// Only variables, which really exist in process environment, will be included in report...
+func (u *configVariablesPool) ClearENV(policy ClearENVPolicy) (*ClearENVReport, error) {
+	report := &ClearENVReport{
+		RemovedKeys: make([]string, 0),
+		KeptKeys:    make([]string, 0),
+	}
+
+	processedKeys := make(map[string]struct{}, len(u.envVariablesList)+len(u.secretVariablesList))
+
+	fieldsList := make([]common.Field, 0, len(u.envVariablesList)+len(u.secretVariablesList))
+	fieldsList = append(fieldsList, u.secretVariablesList...)
+	fieldsList = append(fieldsList, u.envVariablesList...)
+
+	for _, field := range fieldsList {
+		if field.EnvKey == "" {
+			continue
+		}
+
+		if _, isProcessed := processedKeys[field.EnvKey]; isProcessed {
+			continue
+		}
+
+		processedKeys[field.EnvKey] = struct{}{}
+
+		if _, isExists := os.LookupEnv(field.EnvKey); !isExists {
+			continue
+		}
+
+		isMustBeRemoved, err := isFieldVariableMustBeRemoved(policy, field)
+		if err != nil {
+			return nil, u.e.ErrorOnly(err, field.Name)
+		}
+
+		u.logger.VariableScrubbed(field.EnvKey, isMustBeRemoved)
+
+		if !isMustBeRemoved {
+			report.KeptKeys = append(report.KeptKeys, field.EnvKey)
+
+			continue
+		}
+
+		err = os.Unsetenv(field.EnvKey)
+		if err != nil {
+			return nil, u.e.ErrorOnly(err)
+		}
+
+		report.RemovedKeys = append(report.RemovedKeys, field.EnvKey)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,223 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+type TestClearENVConfig struct {
+	DatabaseHost     string `envconfig:"CLEAR_ENV_TEST_DATABASE_HOST"`
+	DatabaseDriver   string `envconfig:"CLEAR_ENV_TEST_DATABASE_DRIVER" scrub:"true"`
+	DatabaseUser     string `envconfig:"CLEAR_ENV_TEST_DATABASE_USER" secret:"true"`
+	DatabasePassword string `envconfig:"CLEAR_ENV_TEST_DATABASE_PASSWORD" secret:"true" keep:"true"`
+	DatabaseName     string `envconfig:"CLEAR_ENV_TEST_DATABASE_NAME" default:"wallet"`
+}
+
+func setClearENVTestVariables() {
+	var InitialEnvVariables = map[string]string{
+		"CLEAR_ENV_TEST_DATABASE_HOST":     "127.0.0.1",
+		"CLEAR_ENV_TEST_DATABASE_DRIVER":   "postgresql",
+		"CLEAR_ENV_TEST_DATABASE_USER":     "env_user",
+		"CLEAR_ENV_TEST_DATABASE_PASSWORD": "env_password",
+	}
+
+	for key, value := range InitialEnvVariables {
+		err := os.Setenv(key, value)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func TestClearENVDefaultPolicy(t *testing.T) {
+	setClearENVTestVariables()
+
+	var MockSecretService = &mockSecretManager{
+		ValuesPool: map[string]string{
+			"CLEAR_ENV_TEST_DATABASE_USER":     "secret_user",
+			"CLEAR_ENV_TEST_DATABASE_PASSWORD": "secret_password",
+		},
+	}
+
+	cfg := &TestClearENVConfig{}
+
+	cfgManagerSrv := NewConfigManager(common.NewMockErrFormatter())
+
+	err := cfgManagerSrv.PrepareTo(cfg).With(MockSecretService).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	report := cfgManagerSrv.GetClearENVReport()
+
+	expectedRemovedKeys := []string{"CLEAR_ENV_TEST_DATABASE_USER", "CLEAR_ENV_TEST_DATABASE_DRIVER"}
+	if !slices.Equal(report.RemovedKeys, expectedRemovedKeys) {
+		t.Errorf("not equal removed keys: %v", report.RemovedKeys)
+	}
+
+	expectedKeptKeys := []string{"CLEAR_ENV_TEST_DATABASE_PASSWORD", "CLEAR_ENV_TEST_DATABASE_HOST"}
+	if !slices.Equal(report.KeptKeys, expectedKeptKeys) {
+		t.Errorf("not equal kept keys: %v", report.KeptKeys)
+	}
+
+	if _, isExists := os.LookupEnv("CLEAR_ENV_TEST_DATABASE_USER"); isExists {
+		t.Errorf("secret variable not removed from environment")
+	}
+
+	if _, isExists := os.LookupEnv("CLEAR_ENV_TEST_DATABASE_PASSWORD"); !isExists {
+		t.Errorf("variable with keep tag removed from environment")
+	}
+
+	if _, isExists := os.LookupEnv("CLEAR_ENV_TEST_DATABASE_HOST"); !isExists {
+		t.Errorf("variable removed from environment by default policy")
+	}
+}
+
+func TestClearENVPolicyAll(t *testing.T) {
+	setClearENVTestVariables()
+
+	var MockSecretService = &mockSecretManager{
+		ValuesPool: map[string]string{
+			"CLEAR_ENV_TEST_DATABASE_USER":     "secret_user",
+			"CLEAR_ENV_TEST_DATABASE_PASSWORD": "secret_password",
+		},
+	}
+
+	cfg := &TestClearENVConfig{}
+
+	cfgManagerSrv := NewConfigManager(common.NewMockErrFormatter()).
+		WithClearENVPolicy(ClearENVPolicyAll)
+
+	err := cfgManagerSrv.PrepareTo(cfg).With(MockSecretService).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	report := cfgManagerSrv.GetClearENVReport()
+
+	expectedRemovedKeys := []string{"CLEAR_ENV_TEST_DATABASE_USER",
+		"CLEAR_ENV_TEST_DATABASE_HOST", "CLEAR_ENV_TEST_DATABASE_DRIVER"}
+	if !slices.Equal(report.RemovedKeys, expectedRemovedKeys) {
+		t.Errorf("not equal removed keys: %v", report.RemovedKeys)
+	}
+
+	if cfg.DatabaseHost != "127.0.0.1" {
+		t.Errorf("not equal DatabaseHost")
+	}
+
+	err = os.Unsetenv("CLEAR_ENV_TEST_DATABASE_PASSWORD")
+	if err != nil {
+		return
+	}
+}
+
+func TestClearENVPolicyAllSources(t *testing.T) {
+	t.Setenv("CLEAR_ENV_TEST_DATABASE_HOST", "127.0.0.1")
+	t.Setenv("CLEAR_ENV_TEST_DATABASE_DRIVER", "postgresql")
+	t.Setenv("CLEAR_ENV_TEST_DATABASE_USER", "env_user")
+	t.Setenv("CLEAR_ENV_TEST_DATABASE_PASSWORD", "env_password")
+	t.Setenv("CLEAR_ENV_TEST_DATABASE_NAME", "env_wallet")
+
+	dirPath := t.TempDir()
+
+	writeConfigMapSnapshot(t, dirPath, "..2024_10_09_10_00_00.000000001", map[string]string{
+		"CLEAR_ENV_TEST_DATABASE_NAME": "configmap_wallet",
+	})
+
+	configMapSrc, err := NewConfigMapDirSource(common.NewMockErrFormatter(), dirPath)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	cfg := &TestClearENVConfig{}
+
+	flagSrc := NewFlagSource(common.NewMockErrFormatter(), "clear-env")
+
+	err = flagSrc.Register(cfg)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	err = flagSrc.Parse([]string{"--clear-env-test-database-host", "10.0.0.1"})
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	secretManager := &mockSecretManager{
+		ValuesPool: map[string]string{
+			"CLEAR_ENV_TEST_DATABASE_USER":     "secret_user",
+			"CLEAR_ENV_TEST_DATABASE_PASSWORD": "secret_password",
+		},
+	}
+
+	cfgManagerSrv := NewConfigManager(common.NewMockErrFormatter()).
+		WithClearENVPolicy(ClearENVPolicyAll)
+
+	err = cfgManagerSrv.PrepareTo(cfg).With(flagSrc, configMapSrc, secretManager).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.DatabaseHost != "10.0.0.1" || cfg.DatabaseName != "configmap_wallet" {
+		t.Errorf("not equal values of flag or configmap sources: %+v", cfg)
+	}
+
+	report := cfgManagerSrv.GetClearENVReport()
+
+	expectedRemovedKeys := []string{"CLEAR_ENV_TEST_DATABASE_USER", "CLEAR_ENV_TEST_DATABASE_DRIVER"}
+	if !slices.Equal(report.RemovedKeys, expectedRemovedKeys) {
+		t.Errorf("not equal removed keys: %v", report.RemovedKeys)
+	}
+
+	expectedKeptKeys := []string{"CLEAR_ENV_TEST_DATABASE_PASSWORD",
+		"CLEAR_ENV_TEST_DATABASE_HOST", "CLEAR_ENV_TEST_DATABASE_NAME"}
+	if !slices.Equal(report.KeptKeys, expectedKeptKeys) {
+		t.Errorf("not equal kept keys: %v", report.KeptKeys)
+	}
+
+	if _, isExists := os.LookupEnv("CLEAR_ENV_TEST_DATABASE_NAME"); !isExists {
+		t.Errorf("variable of configmap source removed from environment")
+	}
+}
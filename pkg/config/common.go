@@ -33,9 +33,11 @@
 package config
 
 import (
+	"context"
 	"time"
 
 	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
 )
 
 type ldFlagManagerService interface {
@@ -47,11 +49,28 @@ type ldFlagManagerService interface {
 	GetBuildDate() time.Time
 }
 
+type releaseVersionProviderService interface {
+	GetReleaseVersion() (*common.SemVer, error)
+}
+
+type buildInfoProviderService interface {
+	GetBuildInfo() *BuildInfo
+}
+
 type dependentConfigService interface {
 	Prepare() error
 	PrepareWith(cfgSrv ...interface{}) error
 }
 
+type environmentRegistryService interface {
+	Lookup(name string) (*EnvironmentDefinition, bool)
+	GetNames() map[string]string
+}
+
+type environmentNameProviderService interface {
+	GetEnvironmentName() string
+}
+
 type configInitService interface {
 	InitWith(cfgSrv ...interface{}) error
 }
@@ -65,6 +84,12 @@ type baseConfigService interface {
 
 	GetHostName() string
 	GetEnvironmentName() string
+	GetEnvironment() *EnvironmentDefinition
+	HasEnvironmentTrait(trait EnvironmentTrait) bool
+	IsProductionLike() bool
+	IsDebugAllowed() bool
+	IsDebugEnabled() bool
+	IsTLSRequired() bool
 	IsProd() bool
 	IsStage() bool
 	IsTest() bool
@@ -81,6 +106,7 @@ type baseConfigService interface {
 	GetBuildNumber() uint64
 	GetBuildDateTS() int64
 	GetBuildDate() time.Time
+	GetBuildInfo() *BuildInfo
 }
 
 type configVariablesPoolService interface {
@@ -88,10 +114,21 @@ type configVariablesPoolService interface {
 	addEnvVariable(field common.Field) error
 }
 
+// variableSourceService - source of env-style variables, which will be checked before process environment...
+type variableSourceService interface {
+	GetSourceName() string
+	LookupValue(key string) (string, bool)
+}
+
 type secretManagerService interface {
 	GetByName(keyName string) (string, bool)
 }
 
+type secretProviderService interface {
+	Get(ctx context.Context, key string) (secrets.Secret, error)
+	GetMany(ctx context.Context, keysList []string) (map[string]secrets.Secret, error)
+}
+
 //nolint:interfacebloat // it's ok here, we need it we must use it as one big interface
 type errorFormatterService interface {
 	ErrorWithCode(err error, code int) error
@@ -0,0 +1,308 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	configMapDirSourceName = "configmap"
+	// configMapDataLinkName - name of symlink, which kubelet atomically swaps on ConfigMap update...
+	configMapDataLinkName = "..data"
+	// configMapReadAttemptsCount - count of snapshot read attempts in case of concurrent symlink swap...
+	configMapReadAttemptsCount = 5
+)
+
+var (
+	ErrConfigMapChangedDuringRead = errors.New("configmap directory changed during read")
+	ErrConfigMapPathIsNotDir      = errors.New("configmap path is not a directory")
+)
+
+var _ variableSourceService = (*configMapDirSource)(nil)
+
+// configMapDirSource - source of config variables, which reads mounted Kubernetes ConfigMap directory.
+// Each regular file in directory is one variable - file name is a key, file content is a value.
+// One trailing line break will be trimmed from file content.
+// Kubelet updates ConfigMap volume by atomic swap of ..data symlink, so source reads all files from
+// resolved ..data target and checks that symlink wasn't swapped during read...
+type configMapDirSource struct {
+	e errorFormatterService
+
+	mu sync.RWMutex
+
+	dirPath  string
+	revision string
+	values   map[string]string
+}
+
+func (s *configMapDirSource) GetSourceName() string {
+	return configMapDirSourceName
+}
+
+// LookupValue returns value of ConfigMap key from last successfully read snapshot...
+func (s *configMapDirSource) LookupValue(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, isExists := s.values[key]
+
+	return value, isExists
+}
+
+// GetRevision returns name of ..data symlink target of last read snapshot.
+// Revision is empty for plain directories without ..data symlink...
+func (s *configMapDirSource) GetRevision() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.revision
+}
+
+// GetValues returns copy of all values from last successfully read snapshot...
+func (s *configMapDirSource) GetValues() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return maps.Clone(s.values)
+}
+
+// Load - read ConfigMap directory snapshot...
+func (s *configMapDirSource) Load() error {
+	_, err := s.Reload()
+	if err != nil {
+		return s.e.ErrorNoWrap(err)
+	}
+
+	return nil
+}
+
+// Reload - read ConfigMap directory snapshot and replace stored values.
+// Returns true if revision or values of ConfigMap were changed since previous read...
+func (s *configMapDirSource) Reload() (bool, error) {
+	revision, values, err := s.readSnapshot()
+	if err != nil {
+		return false, s.e.ErrorNoWrap(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	isChanged := revision != s.revision || !maps.Equal(values, s.values)
+
+	s.revision = revision
+	s.values = values
+
+	return isChanged, nil
+}
+
+// Watch - periodically reload ConfigMap directory and call onChange function after each detected change.
+// Reload errors, e.g. transient read errors during symlink swap, are passed to onError function and don't stop
+// watching - previous snapshot values are kept. Function blocks until context is done...
+func (s *configMapDirSource) Watch(ctx context.Context,
+	interval time.Duration,
+	onChange func(),
+	onError func(err error),
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			isChanged, err := s.Reload()
+			if err != nil {
+				if onError != nil {
+					onError(err)
+				}
+
+				continue
+			}
+
+			if isChanged && onChange != nil {
+				onChange()
+			}
+		}
+	}
+}
+
+func (s *configMapDirSource) readSnapshot() (string, map[string]string, error) {
+	for range configMapReadAttemptsCount {
+		revision, err := s.readRevision()
+		if err != nil {
+			return "", nil, s.e.ErrorNoWrap(err)
+		}
+
+		snapshotPath := s.dirPath
+		if revision != "" {
+			snapshotPath = filepath.Join(s.dirPath, revision)
+		}
+
+		values, isVanished, err := s.readDir(snapshotPath)
+		if err != nil {
+			return "", nil, s.e.ErrorNoWrap(err)
+		}
+
+		if isVanished {
+			if revision == "" {
+				return "", nil, s.e.ErrorOnly(ErrConfigMapChangedDuringRead, s.dirPath)
+			}
+
+			// snapshot directory was removed by kubelet after symlink swap, try again
+			continue
+		}
+
+		currentRevision, err := s.readRevision()
+		if err != nil {
+			return "", nil, s.e.ErrorNoWrap(err)
+		}
+
+		if currentRevision != revision {
+			continue
+		}
+
+		return revision, values, nil
+	}
+
+	return "", nil, s.e.ErrorOnly(ErrConfigMapChangedDuringRead, s.dirPath)
+}
+
+func (s *configMapDirSource) readRevision() (string, error) {
+	target, err := os.Readlink(filepath.Join(s.dirPath, configMapDataLinkName))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", s.e.ErrorOnly(err)
+	}
+
+	return filepath.Base(target), nil
+}
+
+// readDir - read all regular files of directory. Returns true as second value if directory or
+// one of its files was removed during read...
+func (s *configMapDirSource) readDir(dirPath string) (map[string]string, bool, error) {
+	entries, err := os.ReadDir(dirPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, true, nil
+	}
+
+	if err != nil {
+		return nil, false, s.e.ErrorOnly(err)
+	}
+
+	values := make(map[string]string, len(entries))
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		if entry.IsDir() {
+			continue
+		}
+
+		filePath := filepath.Join(dirPath, entry.Name())
+
+		fileInfo, statErr := os.Stat(filePath)
+		if errors.Is(statErr, os.ErrNotExist) {
+			return nil, true, nil
+		}
+
+		if statErr != nil {
+			return nil, false, s.e.ErrorOnly(statErr)
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			continue
+		}
+
+		content, readErr := os.ReadFile(filePath)
+		if errors.Is(readErr, os.ErrNotExist) {
+			return nil, true, nil
+		}
+
+		if readErr != nil {
+			return nil, false, s.e.ErrorOnly(readErr)
+		}
+
+		value := strings.TrimSuffix(string(content), "\n")
+		value = strings.TrimSuffix(value, "\r")
+
+		values[entry.Name()] = value
+	}
+
+	return values, false, nil
+}
+
+// NewConfigMapDirSource - create new source of config variables from mounted ConfigMap directory.
+// Directory snapshot will be read in constructor, source values have higher priority than
+// process environment variables...
+func NewConfigMapDirSource(errFmtSvc errorFormatterService, dirPath string) (*configMapDirSource, error) {
+	errFmtSvc = errFormatterOrDefault(errFmtSvc)
+
+	dirInfo, err := os.Stat(dirPath)
+	if err != nil {
+		return nil, errFmtSvc.ErrorOnly(err)
+	}
+
+	if !dirInfo.IsDir() {
+		return nil, errFmtSvc.ErrorOnly(ErrConfigMapPathIsNotDir, dirPath)
+	}
+
+	srv := &configMapDirSource{
+		e:        errFmtSvc,
+		mu:       sync.RWMutex{},
+		dirPath:  dirPath,
+		revision: "",
+		values:   make(map[string]string),
+	}
+
+	err = srv.Load()
+	if err != nil {
+		return nil, errFmtSvc.ErrorNoWrap(err)
+	}
+
+	return srv, nil
+}
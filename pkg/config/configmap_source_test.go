@@ -0,0 +1,238 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+// writeConfigMapSnapshot - emulate kubelet atomic writer: write new timestamped directory,
+// create temporary ..data symlink and rename it over existing one...
+func writeConfigMapSnapshot(t *testing.T, dirPath, revision string, values map[string]string) {
+	t.Helper()
+
+	snapshotPath := filepath.Join(dirPath, revision)
+
+	err := os.Mkdir(snapshotPath, 0o755)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	for key, value := range values {
+		err = os.WriteFile(filepath.Join(snapshotPath, key), []byte(value), 0o600)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+
+		linkPath := filepath.Join(dirPath, key)
+		if _, statErr := os.Lstat(linkPath); statErr == nil {
+			continue
+		}
+
+		err = os.Symlink(filepath.Join(configMapDataLinkName, key), linkPath)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+	}
+
+	tmpLinkPath := filepath.Join(dirPath, "..data_tmp")
+
+	err = os.Symlink(revision, tmpLinkPath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	err = os.Rename(tmpLinkPath, filepath.Join(dirPath, configMapDataLinkName))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+}
+
+type TestConfigMapDbConfig struct {
+	DatabaseHost string `envconfig:"CONFIGMAP_TEST_DATABASE_HOST" default:"postgresql.local"`
+	DatabasePort uint16 `envconfig:"CONFIGMAP_TEST_DATABASE_PORT" default:"54321"`
+	DatabaseName string `envconfig:"CONFIGMAP_TEST_DATABASE_NAME" default:"wallet"`
+}
+
+func TestConfigMapDirSource(t *testing.T) {
+	dirPath := t.TempDir()
+
+	writeConfigMapSnapshot(t, dirPath, "..2024_10_09_10_00_00.000000001", map[string]string{
+		"CONFIGMAP_TEST_DATABASE_HOST": "10.0.0.1\n",
+		"CONFIGMAP_TEST_DATABASE_PORT": "5432",
+	})
+
+	err := os.Setenv("CONFIGMAP_TEST_DATABASE_PORT", "6432")
+	if err != nil {
+		return
+	}
+
+	configMapSrc, err := NewConfigMapDirSource(common.NewMockErrFormatter(), dirPath)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	cfg := &TestConfigMapDbConfig{}
+
+	err = NewConfigManager(common.NewMockErrFormatter()).PrepareTo(cfg).With(configMapSrc).
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.DatabaseHost != "10.0.0.1" {
+		t.Errorf("not equal DatabaseHost")
+	}
+
+	if cfg.DatabasePort != 5432 {
+		t.Errorf("not equal DatabasePort")
+	}
+
+	if cfg.DatabaseName != "wallet" {
+		t.Errorf("not equal DatabaseName")
+	}
+
+	writeConfigMapSnapshot(t, dirPath, "..2024_10_09_11_00_00.000000002", map[string]string{
+		"CONFIGMAP_TEST_DATABASE_HOST": "10.0.0.2",
+		"CONFIGMAP_TEST_DATABASE_PORT": "5432",
+	})
+
+	isChanged, err := configMapSrc.Reload()
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if !isChanged {
+		t.Errorf("configmap change not detected")
+	}
+
+	if configMapSrc.GetRevision() != "..2024_10_09_11_00_00.000000002" {
+		t.Errorf("not equal revision")
+	}
+
+	value, _ := configMapSrc.LookupValue("CONFIGMAP_TEST_DATABASE_HOST")
+	if value != "10.0.0.2" {
+		t.Errorf("not equal reloaded value")
+	}
+
+	isChanged, err = configMapSrc.Reload()
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if isChanged {
+		t.Errorf("unexpected configmap change")
+	}
+}
+
+func TestConfigMapDirSourceWatch(t *testing.T) {
+	dirPath := t.TempDir()
+
+	writeConfigMapSnapshot(t, dirPath, "..2024_10_09_10_00_00.000000001", map[string]string{
+		"CONFIGMAP_TEST_DATABASE_HOST": "10.0.0.1",
+	})
+
+	configMapSrc, err := NewConfigMapDirSource(common.NewMockErrFormatter(), dirPath)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	// broken ..data symlink - emulation of transient read error
+	err = os.Rename(filepath.Join(dirPath, "..2024_10_09_10_00_00.000000001"),
+		filepath.Join(dirPath, "..removed"))
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errorsChan := make(chan error, 1)
+	changesChan := make(chan struct{}, 1)
+	watchDone := make(chan struct{})
+
+	go func() {
+		defer close(watchDone)
+
+		configMapSrc.Watch(ctx, time.Millisecond, func() {
+			select {
+			case changesChan <- struct{}{}:
+			default:
+			}
+		}, func(err error) {
+			select {
+			case errorsChan <- err:
+			default:
+			}
+		})
+	}()
+
+	select {
+	case <-errorsChan:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("reload error is not reported")
+	}
+
+	if configMapSrc.GetValues()["CONFIGMAP_TEST_DATABASE_HOST"] != "10.0.0.1" {
+		t.Errorf("previous snapshot values must be kept after reload error")
+	}
+
+	writeConfigMapSnapshot(t, dirPath, "..2024_10_09_11_00_00.000000002", map[string]string{
+		"CONFIGMAP_TEST_DATABASE_HOST": "10.0.0.2",
+	})
+
+	select {
+	case <-changesChan:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watch stopped after reload error")
+	}
+
+	if value, _ := configMapSrc.LookupValue("CONFIGMAP_TEST_DATABASE_HOST"); value != "10.0.0.2" {
+		t.Errorf("not equal reloaded value: %s", value)
+	}
+
+	cancel()
+	<-watchDone
+}
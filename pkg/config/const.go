@@ -35,4 +35,14 @@ package config
 const (
 	AppEnvironmentNameVariable = "APP_ENV"
 	AppEnvFilePathVariableName = "APP_LOCAL_ENV_FILE_PATH"
+	// DefaultEnvironmentName - environment name, which is used if APP_ENV variable is not defined.
+	// Must be equal to default tag value of BaseConfig Environment field...
+	DefaultEnvironmentName = EnvDev
+)
+
+const (
+	// AppConfigMinVersionVariable - minimal release version of application binary, which can consume config...
+	AppConfigMinVersionVariable = "APP_CONFIG_MIN_VERSION"
+	// AppConfigMaxVersionVariable - maximal release version of application binary, which can consume config...
+	AppConfigMaxVersionVariable = "APP_CONFIG_MAX_VERSION"
 )
@@ -0,0 +1,222 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"errors"
+	"sync"
+
+	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
+)
+
+var (
+	ErrEnvironmentNameIsEmpty       = errors.New("environment name is empty")
+	ErrEnvironmentAlreadyRegistered = errors.New("environment name or alias already registered")
+	ErrEnvironmentIsNotRegistered   = errors.New("environment is not registered")
+)
+
+// EnvironmentTrait - trait of application environment. Traits can be combined by bitwise OR...
+type EnvironmentTrait uint16
+
+const (
+	// EnvTraitProductionLike - environment serves real users or mirrors production setup...
+	EnvTraitProductionLike EnvironmentTrait = 1 << iota
+	// EnvTraitAllowsDebug - debug mode can be enabled in environment...
+	EnvTraitAllowsDebug
+	// EnvTraitRequiresTLS - all connections in environment must use TLS...
+	EnvTraitRequiresTLS
+	// EnvTraitTesting - environment for automated or manual testing...
+	EnvTraitTesting
+	// EnvTraitDevelopment - environment for development...
+	EnvTraitDevelopment
+	// EnvTraitLocal - environment on developer machine...
+	EnvTraitLocal
+)
+
+// EnvironmentDefinition - description of application environment...
+type EnvironmentDefinition struct {
+	// Name - canonical environment name, value of APP_ENV variable...
+	Name string
+	// Aliases - alternative names of environment, e.g. prod for production...
+	Aliases []string
+	Traits  EnvironmentTrait
+}
+
+// HasTrait - check that environment has all passed traits...
+func (d *EnvironmentDefinition) HasTrait(trait EnvironmentTrait) bool {
+	return d.Traits&trait == trait
+}
+
+func (d *EnvironmentDefinition) IsProductionLike() bool {
+	return d.HasTrait(EnvTraitProductionLike)
+}
+
+func (d *EnvironmentDefinition) AllowsDebug() bool {
+	return d.HasTrait(EnvTraitAllowsDebug)
+}
+
+func (d *EnvironmentDefinition) RequiresTLS() bool {
+	return d.HasTrait(EnvTraitRequiresTLS)
+}
+
+// environmentRegistry - registry of known application environments...
+type environmentRegistry struct {
+	mu sync.RWMutex
+
+	definitions map[string]EnvironmentDefinition
+	// names - map of environment names and aliases to canonical environment names...
+	names map[string]string
+}
+
+// Register - add environment definition to registry...
+func (r *environmentRegistry) Register(definition EnvironmentDefinition) error {
+	if definition.Name == "" {
+		return errfmt.ErrorOnly(ErrEnvironmentNameIsEmpty)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	namesList := append([]string{definition.Name}, definition.Aliases...)
+	for _, name := range namesList {
+		if name == "" {
+			return errfmt.ErrorOnly(ErrEnvironmentNameIsEmpty, definition.Name)
+		}
+
+		if _, isExists := r.names[name]; isExists {
+			return errfmt.ErrorOnly(ErrEnvironmentAlreadyRegistered, name)
+		}
+	}
+
+	definition.Aliases = append([]string(nil), definition.Aliases...)
+
+	r.definitions[definition.Name] = definition
+	for _, name := range namesList {
+		r.names[name] = definition.Name
+	}
+
+	return nil
+}
+
+// Lookup - search environment definition by name or alias...
+func (r *environmentRegistry) Lookup(name string) (*EnvironmentDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	canonicalName, isExists := r.names[name]
+	if !isExists {
+		return nil, false
+	}
+
+	definition := r.definitions[canonicalName]
+
+	return &definition, true
+}
+
+// GetNames returns map of environment names and aliases to canonical environment names...
+func (r *environmentRegistry) GetNames() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]string, len(r.names))
+	for name, canonicalName := range r.names {
+		result[name] = canonicalName
+	}
+
+	return result
+}
+
+// DefaultEnvironmentDefinitions returns definitions of standard environments:
+// local, development, staging, testing and production...
+func DefaultEnvironmentDefinitions() []EnvironmentDefinition {
+	return []EnvironmentDefinition{
+		{
+			Name:    EnvLocal,
+			Aliases: nil,
+			Traits:  EnvTraitLocal | EnvTraitDevelopment | EnvTraitAllowsDebug,
+		},
+		{
+			Name:    EnvDev,
+			Aliases: []string{"dev"},
+			Traits:  EnvTraitDevelopment | EnvTraitAllowsDebug,
+		},
+		{
+			Name:    EnvStaging,
+			Aliases: []string{"stage"},
+			Traits:  EnvTraitProductionLike | EnvTraitRequiresTLS | EnvTraitAllowsDebug,
+		},
+		{
+			Name:    EnvTesting,
+			Aliases: []string{"test"},
+			Traits:  EnvTraitTesting | EnvTraitAllowsDebug,
+		},
+		{
+			Name:    EnvProduction,
+			Aliases: []string{"prod"},
+			Traits:  EnvTraitProductionLike | EnvTraitRequiresTLS,
+		},
+	}
+}
+
+// NewEnvironmentRegistry - create registry of environments with passed definitions.
+// Pass registry to config manager or BaseConfig dependencies list for usage of custom environments, e.g.:
+//
+//	registry, err := NewEnvironmentRegistry(append(DefaultEnvironmentDefinitions(),
+//		EnvironmentDefinition{Name: "preprod", Traits: EnvTraitProductionLike | EnvTraitRequiresTLS})...)
+func NewEnvironmentRegistry(definitionsList ...EnvironmentDefinition) (*environmentRegistry, error) {
+	registry := &environmentRegistry{
+		mu:          sync.RWMutex{},
+		definitions: make(map[string]EnvironmentDefinition, len(definitionsList)),
+		names:       make(map[string]string, len(definitionsList)),
+	}
+
+	for _, definition := range definitionsList {
+		err := registry.Register(definition)
+		if err != nil {
+			return nil, errfmt.ErrorNoWrap(err)
+		}
+	}
+
+	return registry, nil
+}
+
+// NewDefaultEnvironmentRegistry - create registry of environments with standard definitions...
+func NewDefaultEnvironmentRegistry() *environmentRegistry {
+	registry, err := NewEnvironmentRegistry(DefaultEnvironmentDefinitions()...)
+	if err != nil {
+		// standard definitions have unique names and aliases
+		panic(err)
+	}
+
+	return registry
+}
@@ -0,0 +1,164 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+func TestBaseConfigCustomEnvironment(t *testing.T) {
+	var InitialEnvVariables = map[string]string{
+		"APP_ENV":   "preprod",
+		"APP_DEBUG": "true",
+		"APP_STAGE": "preprod",
+	}
+
+	for key, value := range InitialEnvVariables {
+		err := os.Setenv(key, value)
+		if err != nil {
+			return
+		}
+	}
+
+	defer os.Setenv("APP_ENV", EnvDev)
+
+	envRegistry, err := NewEnvironmentRegistry(append(DefaultEnvironmentDefinitions(),
+		EnvironmentDefinition{
+			Name:    "preprod",
+			Aliases: []string{"pre"},
+			Traits:  EnvTraitProductionLike | EnvTraitRequiresTLS,
+		})...)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	baseCfg := &BaseConfig{}
+
+	err = NewConfigManager(common.NewMockErrFormatter()).PrepareTo(baseCfg).
+		With(envRegistry, common.NewMockErrFormatter()).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if baseCfg.GetEnvironment().Name != "preprod" {
+		t.Errorf("not equal environment definition")
+	}
+
+	if !baseCfg.IsProductionLike() || !baseCfg.IsTLSRequired() {
+		t.Errorf("not equal environment traits")
+	}
+
+	if baseCfg.IsDebugAllowed() || baseCfg.IsDebugEnabled() {
+		t.Errorf("debug mode must be disabled in environment without debug trait")
+	}
+
+	if !baseCfg.IsDebug() {
+		t.Errorf("deprecated debug check must return APP_DEBUG value")
+	}
+
+	if baseCfg.IsProd() || baseCfg.IsTest() || baseCfg.IsDev() {
+		t.Errorf("not equal deprecated environment checks")
+	}
+
+	unknownEnvCfg := &BaseConfig{}
+
+	err = os.Setenv("APP_ENV", "canary")
+	if err != nil {
+		return
+	}
+
+	err = NewConfigManager(common.NewMockErrFormatter()).PrepareTo(unknownEnvCfg).
+		With(envRegistry, common.NewMockErrFormatter()).Do(context.Background())
+	if err == nil {
+		t.Errorf("expected error for unknown environment")
+	}
+}
+
+func TestEnvironmentRegistryDuplicates(t *testing.T) {
+	envRegistry := NewDefaultEnvironmentRegistry()
+
+	definition, isExists := envRegistry.Lookup("stage")
+	if !isExists {
+		t.Errorf("environment alias not registered")
+		return
+	}
+
+	if definition.Name != EnvStaging || definition.HasTrait(EnvTraitTesting) {
+		t.Errorf("not equal staging environment definition")
+	}
+
+	names := envRegistry.GetNames()
+	if names["prod"] != EnvProduction {
+		t.Errorf("not equal canonical name of alias")
+	}
+
+	err := envRegistry.Register(EnvironmentDefinition{Name: "preprod", Aliases: []string{"prod"}, Traits: 0})
+	if !errors.Is(err, ErrEnvironmentAlreadyRegistered) {
+		t.Errorf("expected already registered error for duplicated alias: %v", err)
+	}
+
+	if _, isExists = envRegistry.Lookup("preprod"); isExists {
+		t.Errorf("environment with duplicated alias must not be registered")
+	}
+
+	_, err = NewEnvironmentRegistry(append(DefaultEnvironmentDefinitions(),
+		EnvironmentDefinition{Name: EnvStaging, Aliases: nil, Traits: 0})...)
+	if !errors.Is(err, ErrEnvironmentAlreadyRegistered) {
+		t.Errorf("expected already registered error for duplicated name: %v", err)
+	}
+}
+
+func TestBaseConfigDeprecatedChecks(t *testing.T) {
+	baseCfg := &BaseConfig{Environment: EnvStaging, Debug: true}
+
+	// deprecated checks compare APP_ENV value and work before Prepare call
+	if !baseCfg.IsStage() || !baseCfg.IsTest() || baseCfg.IsProd() || baseCfg.IsDev() || baseCfg.IsLocal() {
+		t.Errorf("not equal deprecated environment checks of staging environment")
+	}
+
+	if !baseCfg.IsDebug() || baseCfg.IsDebugEnabled() {
+		t.Errorf("not equal debug checks before Prepare call")
+	}
+
+	baseCfg = &BaseConfig{Environment: EnvLocal, Debug: false}
+	if !baseCfg.IsDev() || !baseCfg.IsLocal() || baseCfg.IsTest() {
+		t.Errorf("not equal deprecated environment checks of local environment")
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+type typedErrorsNestedConfig struct {
+	Required string `envconfig:"TYPED_ERRORS_REQUIRED" required:"true"`
+	Port     int    `envconfig:"TYPED_ERRORS_PORT" default:"80"`
+	PIN      int    `envconfig:"TYPED_ERRORS_PIN" secret:"true"`
+}
+
+type typedErrorsConfig struct {
+	Nested typedErrorsNestedConfig
+}
+
+type scrubbedErrorsConfig struct {
+	SigningKeyIndex int `envconfig:"SCRUBBED_ERRORS_SIGNING_KEY_INDEX" scrub:"true"`
+}
+
+type scrubbedValueTypeErrorsConfig struct {
+	BufferSize ByteSize `envconfig:"SCRUBBED_ERRORS_BUFFER_SIZE" scrub:"true"`
+}
+
+func TestTypedFieldErrors(t *testing.T) {
+	defer os.Unsetenv("TYPED_ERRORS_PORT")
+	defer os.Unsetenv("TYPED_ERRORS_REQUIRED")
+
+	secretManager := &mockSecretManager{ValuesPool: map[string]string{"TYPED_ERRORS_PIN": "secret-pin"}}
+
+	err := NewConfigManager(nil).PrepareTo(&typedErrorsConfig{}).With(secretManager).Do(context.Background())
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Errorf("expected field error: %v", err)
+		return
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrVariableEmptyButRequired) {
+		t.Errorf("expected validation error of required rule: %v", err)
+	}
+
+	if fieldErr.Path != "typedErrorsConfig.Nested.Required" || fieldErr.EnvKey != "TYPED_ERRORS_REQUIRED" {
+		t.Errorf("not equal field path or env key: %s", fieldErr.Path)
+	}
+
+	_ = os.Setenv("TYPED_ERRORS_REQUIRED", "value")
+	_ = os.Setenv("TYPED_ERRORS_PORT", "port")
+
+	err = NewConfigManager(nil).PrepareTo(&typedErrorsConfig{}).With(secretManager).Do(context.Background())
+
+	var parseErr *ParseError
+	if !errors.As(err, &fieldErr) || !errors.As(err, &parseErr) {
+		t.Errorf("expected field error with parse error: %v", err)
+		return
+	}
+
+	if fieldErr.Value != "port" || fieldErr.Source != envSourceName || parseErr.Type != "int" {
+		t.Errorf("not equal field error value, source or type")
+	}
+
+	_ = os.Setenv("TYPED_ERRORS_PORT", "8080")
+
+	err = NewConfigManager(nil).PrepareTo(&typedErrorsConfig{}).With(secretManager).Do(context.Background())
+	if !errors.As(err, &fieldErr) {
+		t.Errorf("expected field error: %v", err)
+		return
+	}
+
+	if fieldErr.SecretKey != "TYPED_ERRORS_PIN" || fieldErr.Value != RedactedValue {
+		t.Errorf("not equal secret key or not redacted value")
+	}
+
+	if strings.Contains(err.Error(), "secret-pin") {
+		t.Errorf("secret value must not be included in error message")
+	}
+}
+
+func TestScrubbedFieldErrors(t *testing.T) {
+	t.Setenv("SCRUBBED_ERRORS_SIGNING_KEY_INDEX", "sensitive-value")
+
+	err := NewConfigManager(nil).PrepareTo(&scrubbedErrorsConfig{}).Do(context.Background())
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Errorf("expected field error: %v", err)
+		return
+	}
+
+	if fieldErr.Value != RedactedValue || fieldErr.EnvKey != "SCRUBBED_ERRORS_SIGNING_KEY_INDEX" {
+		t.Errorf("not equal redacted value or env key of scrubbed field")
+	}
+
+	if strings.Contains(err.Error(), "sensitive-value") {
+		t.Errorf("value of scrubbed field must not be included in error message: %s", err)
+	}
+}
+
+func TestScrubbedFieldErrorsCause(t *testing.T) {
+	t.Setenv("SCRUBBED_ERRORS_BUFFER_SIZE", "4XB")
+
+	err := NewConfigManager(nil).PrepareTo(&scrubbedValueTypeErrorsConfig{}).Do(context.Background())
+	if !errors.Is(err, ErrWrongByteSize) {
+		t.Errorf("expected cause error of scrubbed field: %v", err)
+		return
+	}
+
+	if strings.Contains(err.Error(), "4XB") {
+		t.Errorf("value of scrubbed field must not be included in error message: %s", err)
+	}
+}
@@ -0,0 +1,373 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+const flagSourceName = "flags"
+
+var (
+	ErrFlagNameAlreadyRegistered = errors.New("flag name already registered for another variable")
+)
+
+var _ variableSourceService = (*flagSource)(nil)
+
+// flagValue - flag.Value implementation, which remembers the fact of explicit flag usage...
+type flagValue struct {
+	value  string
+	isBool bool
+	isSet  bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.value
+}
+
+func (v *flagValue) Set(value string) error {
+	v.value = value
+	v.isSet = true
+
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+// flagDescription - information about registered flag, used for generating help message...
+type flagDescription struct {
+	value        *flagValue
+	name         string
+	envKey       string
+	typeName     string
+	usage        string
+	defaultValue string
+	hasDefault   bool
+}
+
+// flagSource - command-line flags source of config variables.
+// Flags are registered for each field of passed config structs, flag names are derived from flag tag or
+// from envconfig key: DATABASE_HOST -> --database-host. Fields with secret tag are not registered...
+type flagSource struct {
+	e errorFormatterService
+
+	flagSet *flag.FlagSet
+
+	applicationName string
+	flagsByEnvKey   map[string]*flagDescription
+	flagsByName     map[string]*flagDescription
+}
+
+func (s *flagSource) GetSourceName() string {
+	return flagSourceName
+}
+
+// LookupValue returns flag value by envconfig key. Only explicitly passed flags will be returned...
+func (s *flagSource) LookupValue(key string) (string, bool) {
+	flagDesc, isExists := s.flagsByEnvKey[key]
+	if !isExists {
+		return "", false
+	}
+
+	if !flagDesc.value.isSet {
+		return "", false
+	}
+
+	return flagDesc.value.value, true
+}
+
+// GetFlagSet returns underlying flag set, can be used for registration of additional application flags...
+func (s *flagSource) GetFlagSet() *flag.FlagSet {
+	return s.flagSet
+}
+
+// Register - register flags for all fields of passed config structs...
+func (s *flagSource) Register(targetList ...interface{}) error {
+	for _, target := range targetList {
+		targetType := reflect.TypeOf(target)
+		if targetType == nil || targetType.Kind() != reflect.Ptr {
+			return s.e.ErrorOnly(ErrPassedStructMustBeAPointer)
+		}
+
+		if targetType.Elem().Kind() != reflect.Struct {
+			return s.e.ErrorOnly(ErrPassedStructMustBeAStructPointer)
+		}
+
+		err := s.registerFields(targetType.Elem())
+		if err != nil {
+			return s.e.ErrorNoWrap(err)
+		}
+	}
+
+	return nil
+}
+
+func (s *flagSource) registerFields(structType reflect.Type) error {
+	for i := range structType.NumField() {
+		structFieldInfo := structType.Field(i)
+		if !structFieldInfo.IsExported() {
+			continue
+		}
+
+		isIgnored, _ := strconv.ParseBool(structFieldInfo.Tag.Get(common.TagIgnored))
+		if isIgnored {
+			continue
+		}
+
+		fieldType := structFieldInfo.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && !common.IsTextUnmarshaler(fieldType) {
+			err := s.registerFields(fieldType)
+			if err != nil {
+				return s.e.ErrorNoWrap(err)
+			}
+
+			continue
+		}
+
+		isSecret, _ := strconv.ParseBool(structFieldInfo.Tag.Get(common.TagSecret))
+		if isSecret {
+			continue
+		}
+
+		envConfigKey := structFieldInfo.Tag.Get(common.TagEnvconfig)
+		if envConfigKey == "" {
+			continue
+		}
+
+		err := s.registerFlag(structFieldInfo, fieldType, envConfigKey)
+		if err != nil {
+			return s.e.ErrorNoWrap(err)
+		}
+	}
+
+	return nil
+}
+
+func (s *flagSource) registerFlag(structFieldInfo reflect.StructField,
+	fieldType reflect.Type,
+	envConfigKey string,
+) error {
+	flagName, hasFlagTag := structFieldInfo.Tag.Lookup(common.TagFlag)
+	if !hasFlagTag || flagName == "" {
+		flagName = strings.ReplaceAll(strings.ToLower(envConfigKey), "_", "-")
+	}
+
+	registeredDesc, isRegistered := s.flagsByName[flagName]
+	if isRegistered {
+		if registeredDesc.envKey != envConfigKey {
+			return s.e.ErrorOnly(ErrFlagNameAlreadyRegistered, flagName)
+		}
+
+		return nil
+	}
+
+	if _, isRegistered = s.flagsByEnvKey[envConfigKey]; isRegistered {
+		return nil
+	}
+
+	// flag with same name can be added by application to flag set before registration of config flags
+	if s.flagSet.Lookup(flagName) != nil {
+		return s.e.ErrorOnly(ErrFlagNameAlreadyRegistered, flagName)
+	}
+
+	defaultValue, hasDefault := structFieldInfo.Tag.Lookup(common.TagDefault)
+
+	flagDesc := &flagDescription{
+		value: &flagValue{
+			value:  "",
+			isBool: fieldType.Kind() == reflect.Bool,
+			isSet:  false,
+		},
+		name:         flagName,
+		envKey:       envConfigKey,
+		typeName:     flagTypeName(fieldType),
+		usage:        structFieldInfo.Tag.Get(common.TagDesc),
+		defaultValue: defaultValue,
+		hasDefault:   hasDefault,
+	}
+
+	s.flagSet.Var(flagDesc.value, flagName, flagDesc.usage)
+
+	s.flagsByName[flagName] = flagDesc
+	s.flagsByEnvKey[envConfigKey] = flagDesc
+
+	return nil
+}
+
+// Parse - parse command-line arguments, without program name. In case of -h or --help argument
+// generated help message will be printed to flag set output and flag.ErrHelp will be returned...
+func (s *flagSource) Parse(arguments []string) error {
+	err := s.flagSet.Parse(arguments)
+	if err != nil {
+		return s.e.ErrorOnly(err)
+	}
+
+	return nil
+}
+
+// PrintHelp - print generated help message for all registered flags. Flags added to flag set by application
+// are printed after generated flags...
+func (s *flagSource) PrintHelp(w io.Writer) {
+	nameList := make([]string, 0, len(s.flagsByName))
+	for name := range s.flagsByName {
+		nameList = append(nameList, name)
+	}
+
+	sort.Strings(nameList)
+
+	builder := strings.Builder{}
+
+	builder.WriteString(fmt.Sprintf("Usage of %s:\n", s.applicationName))
+
+	for _, name := range nameList {
+		flagDesc := s.flagsByName[name]
+
+		builder.WriteString("  --" + flagDesc.name)
+
+		if !flagDesc.value.isBool {
+			builder.WriteString(" " + flagDesc.typeName)
+		}
+
+		builder.WriteString("\n    \t")
+
+		if flagDesc.usage != "" {
+			builder.WriteString(flagDesc.usage + " ")
+		}
+
+		builder.WriteString("(env " + flagDesc.envKey + ")")
+
+		if flagDesc.hasDefault {
+			builder.WriteString(fmt.Sprintf(" (default %q)", flagDesc.defaultValue))
+		}
+
+		builder.WriteString("\n")
+	}
+
+	s.writeApplicationFlags(&builder)
+
+	_, _ = io.WriteString(w, builder.String())
+}
+
+// writeApplicationFlags - write help of flags, which are added to flag set by application, e.g. via GetFlagSet...
+func (s *flagSource) writeApplicationFlags(builder *strings.Builder) {
+	s.flagSet.VisitAll(func(applicationFlag *flag.Flag) {
+		if _, isGenerated := s.flagsByName[applicationFlag.Name]; isGenerated {
+			return
+		}
+
+		typeName, usage := flag.UnquoteUsage(applicationFlag)
+
+		builder.WriteString("  --" + applicationFlag.Name)
+
+		if typeName != "" {
+			builder.WriteString(" " + typeName)
+		}
+
+		builder.WriteString("\n    \t" + usage)
+
+		if applicationFlag.DefValue != "" {
+			builder.WriteString(fmt.Sprintf(" (default %q)", applicationFlag.DefValue))
+		}
+
+		builder.WriteString("\n")
+	})
+}
+
+func flagTypeName(fieldType reflect.Type) string {
+	if fieldType == reflect.TypeOf(time.Duration(0)) {
+		return "duration"
+	}
+
+	if common.IsTextUnmarshaler(fieldType) {
+		return "value"
+	}
+
+	//nolint:exhaustive // it's ok, all other kinds described as value
+	switch fieldType.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return "value"
+	}
+}
+
+// NewFlagSource - create new command-line flags source of config variables.
+// Source must be passed to config manager dependencies list, flags values have higher priority than
+// process environment variables...
+func NewFlagSource(errFmtSvc errorFormatterService, applicationName string) *flagSource {
+	srv := &flagSource{
+		e:               errFormatterOrDefault(errFmtSvc),
+		flagSet:         flag.NewFlagSet(applicationName, flag.ContinueOnError),
+		applicationName: applicationName,
+		flagsByEnvKey:   make(map[string]*flagDescription),
+		flagsByName:     make(map[string]*flagDescription),
+	}
+
+	srv.flagSet.Usage = func() {
+		srv.PrintHelp(srv.flagSet.Output())
+	}
+
+	return srv
+}
@@ -0,0 +1,157 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+type TestFlagsMigratorConfig struct {
+	DatabaseHost     string `envconfig:"FLAGS_TEST_DATABASE_HOST" default:"postgresql.local" desc:"Database host"`
+	DatabaseUser     string `envconfig:"FLAGS_TEST_DATABASE_USER" secret:"true"`
+	BatchSize        uint32 `envconfig:"FLAGS_TEST_BATCH_SIZE" flag:"batch" default:"100"`
+	DryRun           bool   `envconfig:"FLAGS_TEST_DRY_RUN" default:"false"`
+	MigrationsSource string `envconfig:"FLAGS_TEST_MIGRATIONS_SOURCE" required:"true"`
+}
+
+func TestFlagSourceOverwriteEnvVariables(t *testing.T) {
+	var InitialEnvVariables = map[string]string{
+		"FLAGS_TEST_DATABASE_HOST":     "127.0.0.1",
+		"FLAGS_TEST_MIGRATIONS_SOURCE": "file://./env-migrations",
+	}
+
+	for key, value := range InitialEnvVariables {
+		t.Setenv(key, value)
+	}
+
+	cfg := &TestFlagsMigratorConfig{}
+
+	flagSrc := NewFlagSource(common.NewMockErrFormatter(), "migrator")
+
+	err := flagSrc.Register(cfg)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	err = flagSrc.Parse([]string{"--batch", "500", "--flags-test-dry-run",
+		"-flags-test-migrations-source=file://./flag-migrations"})
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	var MockSecretService = &mockSecretManager{
+		ValuesPool: map[string]string{
+			"FLAGS_TEST_DATABASE_USER": "secret_user",
+		},
+	}
+
+	err = NewConfigManager(common.NewMockErrFormatter()).PrepareTo(cfg).With(flagSrc, MockSecretService).
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.DatabaseHost != InitialEnvVariables["FLAGS_TEST_DATABASE_HOST"] {
+		t.Errorf("not equal DatabaseHost")
+	}
+
+	if cfg.BatchSize != 500 {
+		t.Errorf("not equal BatchSize")
+	}
+
+	if !cfg.DryRun {
+		t.Errorf("not equal DryRun")
+	}
+
+	if cfg.MigrationsSource != "file://./flag-migrations" {
+		t.Errorf("not equal MigrationsSource")
+	}
+
+	if flagSrc.GetFlagSet().Lookup("flags-test-database-user") != nil {
+		t.Errorf("secret field must not be registered as flag")
+	}
+}
+
+func TestFlagSourceHelp(t *testing.T) {
+	cfg := &TestFlagsMigratorConfig{}
+
+	flagSrc := NewFlagSource(common.NewMockErrFormatter(), "migrator")
+
+	err := flagSrc.Register(cfg)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	flagSrc.GetFlagSet().String("config-file", "config.json", "Path of `file` with application config")
+
+	helpOutput := &bytes.Buffer{}
+	flagSrc.GetFlagSet().SetOutput(helpOutput)
+
+	err = flagSrc.Parse([]string{"--help"})
+	if err == nil {
+		t.Errorf("expected help error")
+	}
+
+	expectedLines := []string{
+		"Usage of migrator:",
+		"--batch uint",
+		"--flags-test-dry-run\n",
+		"Database host (env FLAGS_TEST_DATABASE_HOST) (default \"postgresql.local\")",
+		"--config-file file\n    \tPath of file with application config (default \"config.json\")",
+	}
+
+	for _, line := range expectedLines {
+		if !strings.Contains(helpOutput.String(), line) {
+			t.Errorf("help output doesn't contain %q", line)
+		}
+	}
+}
+
+func TestFlagSourceApplicationFlagConflict(t *testing.T) {
+	flagSrc := NewFlagSource(common.NewMockErrFormatter(), "migrator")
+	flagSrc.GetFlagSet().Int("batch", 10, "Application batch size")
+
+	err := flagSrc.Register(&TestFlagsMigratorConfig{})
+	if err == nil {
+		t.Errorf("expected error of flag name, which already added by application")
+	}
+}
@@ -33,32 +33,86 @@
 package config
 
 import (
+	"errors"
+	"runtime/debug"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+var (
+	// ErrReleaseVersionIsNotDefined - alias of common.ErrReleaseVersionIsNotDefined error...
+	ErrReleaseVersionIsNotDefined = common.ErrReleaseVersionIsNotDefined
+	ErrInvalidCommitID            = errors.New("commit ID must be 40 hex characters")
+	ErrInvalidShortCommitID       = errors.New("short commit ID must be a prefix of commit ID with length from 7 to 40")
 )
 
 const (
-	ldFlagDefaultReleaseTag  = "v0.0.2-4c3452b-100500"
+	ldFlagDefaultReleaseTag  = "v0.0.0-devel"
 	ldFlagDefaultCommit      = "0000000000000000000000000000000000000000"
 	ldFlagDefaultShortCommit = "00000000"
-	ldFlagDefaultBuildNumber = 100500
+	ldFlagDefaultBuildNumber = 0
+
+	commitIDLength         = 40
+	shortCommitIDMinLength = 7
 )
 
 var _ ldFlagManagerService = (*ldFlagManager)(nil)
 
 type ldFlagManager struct {
 	buildDateAt   time.Time
+	vcsTime       time.Time
 	releaseTag    string
 	commitID      string
 	shortCommitID string
+	vcsType       string
+	goVersion     string
+	modulePath    string
+	moduleVersion string
+	dependencies  []BuildDependency
 	buildNumber   uint64
-	buildDateTS   uint64
+	vcsModified   bool
+	// isVCSCommitID - commit ID filled by vcs.revision of build info instead of ldflags value...
+	isVCSCommitID bool
 }
 
 func (m *ldFlagManager) GetReleaseTag() string {
 	return m.releaseTag
 }
 
+// GetReleaseVersion returns release tag parsed as semantic version...
+func (m *ldFlagManager) GetReleaseVersion() (*common.SemVer, error) {
+	return common.ParseSemVer(m.releaseTag)
+}
+
+// IsReleaseVersionAtLeast - check that release version is greater than or equal to passed version...
+func (m *ldFlagManager) IsReleaseVersionAtLeast(version string) (bool, error) {
+	err := m.CheckVersionConstraint(version, "")
+	if err != nil {
+		if errors.Is(err, common.ErrVersionLowerThanMinimum) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CheckVersionConstraint - check that release version is in range of minimal and maximal versions.
+// Empty minVersion or maxVersion means no limit...
+func (m *ldFlagManager) CheckVersionConstraint(minVersion, maxVersion string) error {
+	releaseVersion, err := m.GetReleaseVersion()
+	if err != nil {
+		return err
+	}
+
+	return common.CheckVersionConstraint(releaseVersion, minVersion, maxVersion)
+}
+
 func (m *ldFlagManager) GetCommitID() string {
 	return m.commitID
 }
@@ -79,22 +133,99 @@ func (m *ldFlagManager) GetBuildDate() time.Time {
 	return m.buildDateAt
 }
 
-func newDefaultLdFlagManager() *ldFlagManager {
-	currTime := time.Now()
+// GetBuildInfo returns build metadata - ldflags values merged with runtime/debug build info...
+func (m *ldFlagManager) GetBuildInfo() *BuildInfo {
+	return &BuildInfo{
+		ReleaseTag:    m.releaseTag,
+		CommitID:      m.commitID,
+		ShortCommitID: m.shortCommitID,
+		BuildNumber:   m.buildNumber,
+		BuildDate:     m.buildDateAt,
+		VCSType:       m.vcsType,
+		VCSTime:       m.vcsTime,
+		VCSModified:   m.vcsModified,
+		GoVersion:     m.goVersion,
+		ModulePath:    m.modulePath,
+		ModuleVersion: m.moduleVersion,
+		Dependencies:  append([]BuildDependency(nil), m.dependencies...),
+	}
+}
 
+// newStubLdFlagManager - create ldflag manager with stub values...
+func newStubLdFlagManager() *ldFlagManager {
 	return &ldFlagManager{
 		releaseTag:    ldFlagDefaultReleaseTag,
 		commitID:      ldFlagDefaultCommit,
 		shortCommitID: ldFlagDefaultShortCommit,
 		buildNumber:   ldFlagDefaultBuildNumber,
-		buildDateTS:   uint64(currTime.Unix()),
-		buildDateAt:   time.Now(),
+		buildDateAt:   time.Unix(0, 0),
+		vcsTime:       time.Time{},
+		vcsType:       "",
+		vcsModified:   false,
+		isVCSCommitID: false,
+		goVersion:     "",
+		modulePath:    "",
+		moduleVersion: "",
+		dependencies:  nil,
 	}
 }
 
-//nolint:gochecknoglobals
-var ldFlagsSrv *ldFlagManager
+// newDefaultLdFlagManager - create ldflag manager with values from runtime/debug build info.
+// Stub values will be used if build info is not available...
+func newDefaultLdFlagManager() *ldFlagManager {
+	buildInfo, _ := debug.ReadBuildInfo()
+
+	manager := newStubLdFlagManager()
+	manager.fillByBuildInfo(buildInfo)
+
+	return manager
+}
+
+// validate - check release tag, commit ID and short commit ID values. Short commit ID must contain from 7 to 40
+// hex characters and must be a prefix of commit ID from ldflags. Commit ID of build info isn't authoritative:
+// short commit ID from ldflags need not be a prefix of it, e.g. binary was built from other checkout...
+func (m *ldFlagManager) validate() error {
+	_, err := common.ParseSemVer(m.releaseTag)
+	if err != nil {
+		return err
+	}
+
+	if !isValidCommitID(m.commitID) {
+		return ErrInvalidCommitID
+	}
 
+	if len(m.shortCommitID) < shortCommitIDMinLength || len(m.shortCommitID) > commitIDLength ||
+		!isHexString(m.shortCommitID) {
+		return ErrInvalidShortCommitID
+	}
+
+	if !m.isVCSCommitID && !strings.HasPrefix(m.commitID, m.shortCommitID) {
+		return ErrInvalidShortCommitID
+	}
+
+	return nil
+}
+
+func isValidCommitID(commitID string) bool {
+	return len(commitID) == commitIDLength && isHexString(commitID)
+}
+
+func isHexString(value string) bool {
+	for _, symbol := range value {
+		isHex := (symbol >= '0' && symbol <= '9') ||
+			(symbol >= 'a' && symbol <= 'f') ||
+			(symbol >= 'A' && symbol <= 'F')
+		if !isHex {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewLdFlagsManager - create ldflag manager by values from ldflags. Each call returns new instance.
+// Release tag must be a semantic version, commit ID - 40 hex characters,
+// short commit ID - prefix of commit ID...
 func NewLdFlagsManager(
 	errFmtSvc errorFormatterService,
 	releaseTag,
@@ -103,28 +234,110 @@ func NewLdFlagsManager(
 	buildNumber,
 	buildDateTS string,
 ) (*ldFlagManager, error) {
-	if ldFlagsSrv != nil {
-		return ldFlagsSrv, nil
-	}
+	errFmtSvc = errFormatterOrDefault(errFmtSvc)
 
-	buildDateTSRaw, err := strconv.ParseUint(buildDateTS, 10, 0)
+	buildDateTSRaw, err := strconv.ParseInt(buildDateTS, 10, 64)
 	if err != nil {
-		return nil, errFmtSvc.ErrorOnly(err)
+		return nil, errFmtSvc.ErrorOnly(err, buildDateTS)
 	}
 
 	buildNumberRaw, err := strconv.ParseUint(buildNumber, 10, 0)
 	if err != nil {
-		return nil, errFmtSvc.ErrorOnly(err)
+		return nil, errFmtSvc.ErrorOnly(err, buildNumber)
+	}
+
+	manager := newStubLdFlagManager()
+	manager.releaseTag = releaseTag
+	manager.commitID = commitID
+	manager.shortCommitID = shortCommitID
+	manager.buildNumber = buildNumberRaw
+	manager.buildDateAt = time.Unix(buildDateTSRaw, 0)
+
+	err = manager.validate()
+	if err != nil {
+		return nil, errFmtSvc.ErrorOnly(err, releaseTag, commitID, shortCommitID)
+	}
+
+	return manager, nil
+}
+
+// LdFlagsFixture - values of ldflag manager for tests. Empty values will be replaced by stub values,
+// empty ShortCommitID - by prefix of CommitID...
+type LdFlagsFixture struct {
+	BuildDate     time.Time
+	ReleaseTag    string
+	CommitID      string
+	ShortCommitID string
+	BuildNumber   uint64
+}
+
+// NewLdFlagsManagerFixture - create ldflag manager for tests. Panics in case of invalid fixture values...
+func NewLdFlagsManagerFixture(fixture LdFlagsFixture) *ldFlagManager {
+	manager := newStubLdFlagManager()
+	manager.buildNumber = fixture.BuildNumber
+
+	if fixture.ReleaseTag != "" {
+		manager.releaseTag = fixture.ReleaseTag
+	}
+
+	if fixture.CommitID != "" {
+		manager.commitID = fixture.CommitID
+		manager.shortCommitID = fixture.CommitID[:min(len(fixture.CommitID), len(ldFlagDefaultShortCommit))]
+	}
+
+	if fixture.ShortCommitID != "" {
+		manager.shortCommitID = fixture.ShortCommitID
 	}
 
-	ldFlagsSrv = &ldFlagManager{
-		releaseTag:    releaseTag,
-		commitID:      commitID,
-		shortCommitID: shortCommitID,
-		buildNumber:   buildNumberRaw,
-		buildDateTS:   buildDateTSRaw,
-		buildDateAt:   time.Unix(int64(buildDateTSRaw), 0),
+	if !fixture.BuildDate.IsZero() {
+		manager.buildDateAt = fixture.BuildDate
 	}
 
-	return ldFlagsSrv, nil
+	err := manager.validate()
+	if err != nil {
+		panic(err)
+	}
+
+	return manager
+}
+
+//nolint:gochecknoglobals // process-wide default ldflag manager, see SetDefaultLdFlagsManager
+var (
+	defaultLdFlagsManagerMu sync.RWMutex
+	defaultLdFlagsManager   *ldFlagManager
+)
+
+// SetDefaultLdFlagsManager - set process-wide default ldflag manager. Default ldflag manager used by
+// NewBaseConfig function...
+func SetDefaultLdFlagsManager(manager *ldFlagManager) {
+	defaultLdFlagsManagerMu.Lock()
+	defer defaultLdFlagsManagerMu.Unlock()
+
+	defaultLdFlagsManager = manager
+}
+
+// DefaultLdFlagsManager returns process-wide default ldflag manager. If default manager was not set,
+// manager with runtime/debug build info values will be created...
+func DefaultLdFlagsManager() *ldFlagManager {
+	defaultLdFlagsManagerMu.RLock()
+	manager := defaultLdFlagsManager
+	defaultLdFlagsManagerMu.RUnlock()
+
+	if manager != nil {
+		return manager
+	}
+
+	defaultLdFlagsManagerMu.Lock()
+	defer defaultLdFlagsManagerMu.Unlock()
+
+	if defaultLdFlagsManager == nil {
+		defaultLdFlagsManager = newDefaultLdFlagManager()
+	}
+
+	return defaultLdFlagsManager
+}
+
+// ResetDefaultLdFlagsManager - remove process-wide default ldflag manager, e.g. between tests...
+func ResetDefaultLdFlagsManager() {
+	SetDefaultLdFlagsManager(nil)
 }
@@ -0,0 +1,128 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+func TestNewLdFlagsManager(t *testing.T) {
+	const commitID = "4c3452b1a5d6e7f8091a2b3c4d5e6f708192a3b4"
+
+	firstManager, err := NewLdFlagsManager(common.NewMockErrFormatter(),
+		"v1.2.3", commitID, "4c3452b", "42", "1728468000")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	secondManager, err := NewLdFlagsManager(common.NewMockErrFormatter(),
+		"v1.2.4", commitID, commitID[:8], "43", "1728468001")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if firstManager.GetReleaseTag() != "v1.2.3" || secondManager.GetReleaseTag() != "v1.2.4" {
+		t.Errorf("each call must return new instance with passed values")
+	}
+
+	if firstManager.GetBuildDateTS() != 1728468000 || firstManager.GetBuildNumber() != 42 {
+		t.Errorf("not equal build date or build number")
+	}
+
+	invalidValuesList := [][5]string{
+		{"release", commitID, commitID[:8], "1", "1"},
+		{"v1.2.3", "4c3452b1", "4c3452b1", "1", "1"},
+		{"v1.2.3", "zc3452b1a5d6e7f8091a2b3c4d5e6f708192a3b4", "zc3452b1", "1", "1"},
+		{"v1.2.3", commitID, "5c3452b1", "1", "1"},
+		{"v1.2.3", commitID, "4c34", "1", "1"},
+		{"v1.2.3", commitID, commitID[:8], "-1", "1"},
+		{"v1.2.3", commitID, commitID[:8], "1", "yesterday"},
+	}
+
+	for _, values := range invalidValuesList {
+		_, err = NewLdFlagsManager(common.NewMockErrFormatter(),
+			values[0], values[1], values[2], values[3], values[4])
+		if err == nil {
+			t.Errorf("expected error for values %v", values)
+		}
+	}
+}
+
+func TestLdFlagsManagerFixtureAndDefault(t *testing.T) {
+	defer ResetDefaultLdFlagsManager()
+
+	buildDate := time.Date(2024, 10, 9, 10, 0, 0, 0, time.UTC)
+
+	fixture := NewLdFlagsManagerFixture(LdFlagsFixture{
+		ReleaseTag:    "v1.0.0-rc.1",
+		CommitID:      "4c3452b1a5d6e7f8091a2b3c4d5e6f708192a3b4",
+		ShortCommitID: "",
+		BuildNumber:   7,
+		BuildDate:     buildDate,
+	})
+
+	if fixture.GetShortCommitID() != "4c3452b1" || !fixture.GetBuildDate().Equal(buildDate) {
+		t.Errorf("not equal fixture values")
+	}
+
+	SetDefaultLdFlagsManager(fixture)
+
+	if NewBaseConfig("test").GetReleaseTag() != "v1.0.0-rc.1" {
+		t.Errorf("default ldflag manager must be used by NewBaseConfig")
+	}
+
+	ResetDefaultLdFlagsManager()
+
+	if DefaultLdFlagsManager() == fixture {
+		t.Errorf("default ldflag manager must be reset")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for invalid fixture")
+		}
+	}()
+
+	NewLdFlagsManagerFixture(LdFlagsFixture{
+		ReleaseTag:    "",
+		CommitID:      "not-a-commit",
+		ShortCommitID: "",
+		BuildNumber:   0,
+		BuildDate:     time.Time{},
+	})
+}
@@ -0,0 +1,107 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+type loggedConfig struct {
+	Host     string `envconfig:"LOGGED_CONFIG_HOST" default:"localhost"`
+	Token    string `envconfig:"LOGGED_CONFIG_TOKEN" scrub:"true"`
+	Password string `envconfig:"LOGGED_CONFIG_PASSWORD" secret:"true"`
+}
+
+func (c *loggedConfig) Prepare() error {
+	return nil
+}
+
+func (c *loggedConfig) PrepareWith(_ ...interface{}) error {
+	return nil
+}
+
+func TestConfigManagerLogger(t *testing.T) {
+	defer os.Unsetenv("LOGGED_CONFIG_TOKEN")
+
+	err := os.Setenv("LOGGED_CONFIG_TOKEN", "plain-token-value")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	logBuffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logBuffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	levels := DefaultLogLevels()
+	levels.Field = slog.LevelInfo
+	levels.Hook = slog.LevelWarn
+
+	err = NewConfigManager(nil).PrepareTo(&loggedConfig{}).With(logger, levels, &mockSecretManager{
+		ValuesPool: map[string]string{"LOGGED_CONFIG_PASSWORD": "secret-password-value"},
+	}).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	logText := logBuffer.String()
+
+	if strings.Contains(logText, "secret-password-value") || strings.Contains(logText, "plain-token-value") {
+		t.Errorf("sensitive values must be redacted: %s", logText)
+	}
+
+	expectedRecordsList := []string{
+		`msg="config source discovered" source=env`,
+		`msg="config field resolved" path=loggedConfig.Host key=LOGGED_CONFIG_HOST source=default value=localhost`,
+		`key=LOGGED_CONFIG_TOKEN source=env value=[REDACTED]`,
+		`key=LOGGED_CONFIG_PASSWORD source=secret value=[REDACTED]`,
+		`msg="config prepare hook finished" path=loggedConfig hook=Prepare`,
+		`msg="config environment variable scrubbing" key=LOGGED_CONFIG_TOKEN removed=true`,
+	}
+
+	for _, expectedRecord := range expectedRecordsList {
+		if !strings.Contains(logText, expectedRecord) {
+			t.Errorf("log record not found: %s", expectedRecord)
+		}
+	}
+
+	if strings.Contains(logText, "config field default value applied") ||
+		strings.Contains(logText, "config secret fetched") {
+		t.Errorf("debug level records must be filtered by handler level")
+	}
+}
@@ -0,0 +1,272 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
+)
+
+var errSecretStorageUnavailable = errors.New("secret storage unavailable")
+
+type mockSecretProvider struct {
+	err        error
+	ValuesPool map[string]string
+}
+
+func (m *mockSecretProvider) Get(ctx context.Context, key string) (secrets.Secret, error) {
+	if ctx.Err() != nil {
+		return secrets.Secret{}, ctx.Err()
+	}
+
+	if m.err != nil {
+		return secrets.Secret{}, m.err
+	}
+
+	value, isExists := m.ValuesPool[key]
+	if !isExists {
+		return secrets.Secret{}, secrets.ErrSecretNotFound
+	}
+
+	return secrets.Secret{Key: key, Value: value, Version: "1"}, nil
+}
+
+func (m *mockSecretProvider) GetMany(ctx context.Context, keysList []string) (map[string]secrets.Secret, error) {
+	return secrets.GetManyByOne(ctx, m, keysList)
+}
+
+type secretProviderConfig struct {
+	Password string `envconfig:"SECRET_PROVIDER_PASSWORD" secret:"true"`
+	User     string `envconfig:"SECRET_PROVIDER_USER" default:"user"`
+}
+
+func TestSecretProvider(t *testing.T) {
+	provider := &mockSecretProvider{
+		err:        nil,
+		ValuesPool: map[string]string{"SECRET_PROVIDER_PASSWORD": "password"},
+	}
+
+	cfg := &secretProviderConfig{}
+
+	err := NewConfigManager(nil).PrepareTo(cfg).With(provider).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.Password != "password" || cfg.User != "user" {
+		t.Errorf("not equal config values: %+v", cfg)
+	}
+
+	provider.err = errSecretStorageUnavailable
+
+	err = NewConfigManager(nil).PrepareTo(&secretProviderConfig{}).With(provider).Do(context.Background())
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || !errors.Is(err, errSecretStorageUnavailable) {
+		t.Errorf("expected field error with secret storage error: %v", err)
+		return
+	}
+
+	if fieldErr.SecretKey != "SECRET_PROVIDER_PASSWORD" {
+		t.Errorf("not equal secret key: %s", fieldErr.SecretKey)
+	}
+
+	provider.err = nil
+	provider.ValuesPool = map[string]string{}
+
+	cfg = &secretProviderConfig{}
+
+	err = NewConfigManager(nil).PrepareTo(cfg).With(provider).Do(context.Background())
+	if err != nil {
+		t.Errorf("absent secret must not be an error: %s", err)
+		return
+	}
+
+	if cfg.Password != "" {
+		t.Errorf("absent secret must not be filled: %s", cfg.Password)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = NewConfigManager(nil).PrepareTo(&secretProviderConfig{}).With(provider).Do(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled error: %v", err)
+	}
+}
+
+type batchSecretProvider struct {
+	mockSecretProvider
+	keysList   []string
+	failedKeys map[string]bool
+	callsCount int
+}
+
+func (m *batchSecretProvider) Get(ctx context.Context, key string) (secrets.Secret, error) {
+	if m.failedKeys[key] {
+		return secrets.Secret{}, errSecretStorageUnavailable
+	}
+
+	return m.mockSecretProvider.Get(ctx, key)
+}
+
+func (m *batchSecretProvider) GetMany(ctx context.Context, keysList []string) (map[string]secrets.Secret, error) {
+	m.callsCount++
+	m.keysList = keysList
+
+	return secrets.GetManyConcurrently(ctx, m, keysList, 2)
+}
+
+type batchSecretsNestedConfig struct {
+	Token    string `envconfig:"BATCH_SECRETS_TOKEN" secret:"true"`
+	Password string `envconfig:"BATCH_SECRETS_PASSWORD" secret:"true"`
+}
+
+type batchSecretsConfig struct {
+	Nested  *batchSecretsNestedConfig
+	Key     string `envconfig:"BATCH_SECRETS_KEY" secret:"true"`
+	Ignored string `envconfig:"BATCH_SECRETS_IGNORED" secret:"true" ignored:"true"`
+	Other   batchSecretsNestedConfig
+}
+
+func TestSecretsBatchResolution(t *testing.T) {
+	provider := &batchSecretProvider{
+		mockSecretProvider: mockSecretProvider{
+			err: nil,
+			ValuesPool: map[string]string{
+				"BATCH_SECRETS_TOKEN":    "token",
+				"BATCH_SECRETS_PASSWORD": "password",
+				"BATCH_SECRETS_KEY":      "key",
+			},
+		},
+		keysList:   nil,
+		failedKeys: map[string]bool{},
+		callsCount: 0,
+	}
+
+	cfg := &batchSecretsConfig{}
+
+	err := NewConfigManager(nil).PrepareTo(cfg).With(provider).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if provider.callsCount != 1 {
+		t.Errorf("all secrets must be fetched by one GetMany call: %d", provider.callsCount)
+	}
+
+	expectedKeys := "BATCH_SECRETS_TOKEN,BATCH_SECRETS_PASSWORD,BATCH_SECRETS_KEY"
+	if strings.Join(provider.keysList, ",") != expectedKeys {
+		t.Errorf("not equal discovered keys: %v", provider.keysList)
+	}
+
+	if cfg.Nested.Token != "token" || cfg.Other.Password != "password" || cfg.Key != "key" {
+		t.Errorf("not equal config values: %+v", cfg)
+	}
+
+	provider.failedKeys = map[string]bool{"BATCH_SECRETS_KEY": true, "BATCH_SECRETS_TOKEN": true}
+
+	err = NewConfigManager(nil).PrepareTo(&batchSecretsConfig{}).With(provider).Do(context.Background())
+	if !errors.Is(err, errSecretStorageUnavailable) {
+		t.Errorf("expected secret storage error: %v", err)
+		return
+	}
+
+	var joinedErr interface{ Unwrap() []error }
+	if !errors.As(err, &joinedErr) {
+		t.Errorf("expected aggregated errors: %v", err)
+		return
+	}
+
+	pathsList := make([]string, 0)
+	for _, itemErr := range joinedErr.Unwrap() {
+		var fieldErr *FieldError
+		if errors.As(itemErr, &fieldErr) {
+			pathsList = append(pathsList, fieldErr.Path)
+		}
+	}
+
+	expectedPaths := "batchSecretsConfig.Nested.Token,batchSecretsConfig.Key,batchSecretsConfig.Other.Token"
+	if strings.Join(pathsList, ",") != expectedPaths {
+		t.Errorf("not equal paths of failed fields: %v", pathsList)
+	}
+}
+
+type secretReferencesConfig struct {
+	Password string `envconfig:"SECRET_REFERENCES_PASSWORD" secret:"true"`
+	Token    string `envconfig:"SECRET_REFERENCES_TOKEN" secret:"true" secret_name:"vault:wallet/hot#token@v3"`
+	Seed     string `envconfig:"SECRET_REFERENCES_SEED" secret:"true" secret_name:"vault:wallet/seed|default-seed"`
+	Wrong    string `envconfig:"SECRET_REFERENCES_WRONG" secret:"true"`
+}
+
+func TestSecretReferences(t *testing.T) {
+	t.Setenv("SECRET_REFERENCES_PASSWORD", "!secret:vault:wallet/hot#password")
+
+	vaultProvider := &mockSecretProvider{
+		err: nil,
+		ValuesPool: map[string]string{
+			"wallet/hot#password": "password",
+			"wallet/hot#token@v3": "token",
+		},
+	}
+	router := secrets.NewSchemeRouter(&mockSecretProvider{err: nil, ValuesPool: map[string]string{}},
+		map[string]secrets.SecretProvider{"vault": vaultProvider})
+
+	cfg := &secretReferencesConfig{}
+
+	err := NewConfigManager(nil).PrepareTo(cfg).With(router).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.Password != "password" || cfg.Token != "token" || cfg.Seed != "default-seed" || cfg.Wrong != "" {
+		t.Errorf("not equal config values: %+v", cfg)
+	}
+
+	t.Setenv("SECRET_REFERENCES_WRONG", "!secret:vault:wallet/hot#")
+
+	err = NewConfigManager(nil).PrepareTo(&secretReferencesConfig{}).With(router).Do(context.Background())
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || !errors.Is(err, secrets.ErrWrongSecretReference) ||
+		fieldErr.Path != "secretReferencesConfig.Wrong" {
+		t.Errorf("expected field error of wrong secret reference: %v", err)
+	}
+}
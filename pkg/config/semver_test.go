@@ -0,0 +1,140 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+func TestLdFlagsManagerReleaseVersion(t *testing.T) {
+	manager := newStubLdFlagManager()
+	manager.releaseTag = "v1.4.0-rc.2+build.17"
+
+	releaseVersion, err := manager.GetReleaseVersion()
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if releaseVersion.Major != 1 || releaseVersion.Minor != 4 || releaseVersion.Patch != 0 {
+		t.Errorf("not equal version core")
+	}
+
+	if releaseVersion.String() != "1.4.0-rc.2+build.17" || !releaseVersion.IsPrerelease() {
+		t.Errorf("not equal prerelease or build metadata")
+	}
+
+	orderedVersionsList := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.4.0-rc.2", "1.4.0",
+	}
+
+	for i := 1; i < len(orderedVersionsList); i++ {
+		left := common.MustParseSemVer(orderedVersionsList[i-1])
+		right := common.MustParseSemVer(orderedVersionsList[i])
+
+		if !left.LessThan(right) || !right.GreaterThan(left) {
+			t.Errorf("wrong precedence of %s and %s", left, right)
+		}
+	}
+
+	if !common.MustParseSemVer("1.0.0+build.1").Equal(common.MustParseSemVer("v1.0.0+build.2")) {
+		t.Errorf("build metadata must be ignored in comparison")
+	}
+
+	for _, invalidVersion := range []string{"1.2", "01.2.3", "1.2.3-01", "1.2.3-", "1.2.3+a..b", "version"} {
+		_, err = common.ParseSemVer(invalidVersion)
+		if err == nil {
+			t.Errorf("expected error for invalid version %s", invalidVersion)
+		}
+	}
+
+	isAtLeast, err := manager.IsReleaseVersionAtLeast("v1.4.0-rc.1")
+	if err != nil || !isAtLeast {
+		t.Errorf("release version must be at least v1.4.0-rc.1")
+	}
+
+	isAtLeast, err = manager.IsReleaseVersionAtLeast("v1.4.0")
+	if err != nil || isAtLeast {
+		t.Errorf("prerelease version must be lower than release")
+	}
+
+	if manager.CheckVersionConstraint("", "v1.3.9") == nil {
+		t.Errorf("expected error for version higher than maximum")
+	}
+}
+
+func TestVarPoolReleaseVersionConstraint(t *testing.T) {
+	defer os.Unsetenv(AppConfigMinVersionVariable)
+	defer os.Unsetenv(AppConfigMaxVersionVariable)
+
+	manager := newStubLdFlagManager()
+	manager.releaseTag = "v1.4.2"
+
+	type versionedConfig struct {
+		Name string `envconfig:"VERSIONED_CONFIG_NAME" default:"versioned"`
+	}
+
+	constraintsList := []struct {
+		minVersion string
+		maxVersion string
+		isValid    bool
+	}{
+		{minVersion: "v1.4.0", maxVersion: "", isValid: true},
+		{minVersion: "v1.4.0", maxVersion: "v1.5.0", isValid: true},
+		{minVersion: "v1.5.0", maxVersion: "", isValid: false},
+		{minVersion: "", maxVersion: "v1.4.2-rc.1", isValid: false},
+	}
+
+	for _, constraint := range constraintsList {
+		_ = os.Setenv(AppConfigMinVersionVariable, constraint.minVersion)
+		_ = os.Setenv(AppConfigMaxVersionVariable, constraint.maxVersion)
+
+		err := newConfigVarsPool(common.NewMockErrFormatter(), nil,
+			&versionedConfig{}, []interface{}{manager}).Process(context.Background())
+		if (err == nil) != constraint.isValid {
+			t.Errorf("wrong result of constraint check: min %s, max %s",
+				constraint.minVersion, constraint.maxVersion)
+		}
+	}
+
+	err := newConfigVarsPool(common.NewMockErrFormatter(), nil,
+		&versionedConfig{}, []interface{}{}).Process(context.Background())
+	if err == nil {
+		t.Errorf("expected error for constraint without release version provider")
+	}
+}
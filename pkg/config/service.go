@@ -35,8 +35,11 @@ package config
 import (
 	"context"
 	"os"
+	"sync"
+	"time"
 
 	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
 
 	"github.com/joho/godotenv"
 )
@@ -89,16 +92,30 @@ func (m *targetConfigWrapper) PrepareWith(cfgDependenciesSvcList ...interface{})
 type configManager struct {
 	e errorFormatterService
 
-	secretsSrv secretManagerService
+	secretsSrv secretProviderService
 
 	wrapperConfig *targetConfigWrapper
+
+	clearENVPolicy ClearENVPolicy
+
+	// stateMu - guards results of last Do call, which can be read concurrently with config reload,
+	// e.g. by introspection HTTP handlers...
+	stateMu                sync.RWMutex
+	clearENVReport         *ClearENVReport
+	effectiveVariablesList []EffectiveVariable
+	lastPreparedAt         time.Time
 }
 
 func (m *configManager) With(dependenciesList ...interface{}) *configManager {
 	for _, cfgSrv := range dependenciesList {
 		switch castedDependency := cfgSrv.(type) {
-		case secretManagerService:
+		case secretProviderService:
 			m.secretsSrv = castedDependency
+		case secretManagerService:
+			m.secretsSrv = secrets.AdaptSecretManager(castedDependency)
+		case errorFormatterService:
+			m.e = castedDependency
+			m.wrapperConfig.e = castedDependency
 		default:
 			continue
 		}
@@ -109,6 +126,37 @@ func (m *configManager) With(dependenciesList ...interface{}) *configManager {
 	return m
 }
 
+// WithClearENVPolicy - set policy of process environment scrubbing after config preparation...
+func (m *configManager) WithClearENVPolicy(policy ClearENVPolicy) *configManager {
+	m.clearENVPolicy = policy
+
+	return m
+}
+
+// GetClearENVReport returns report of process environment scrubbing of last Do call...
+func (m *configManager) GetClearENVReport() *ClearENVReport {
+	m.stateMu.RLock()
+	defer m.stateMu.RUnlock()
+
+	return m.clearENVReport
+}
+
+// GetEffectiveVariables returns variables of config, prepared by last Do call. Sensitive values are redacted...
+func (m *configManager) GetEffectiveVariables() []EffectiveVariable {
+	m.stateMu.RLock()
+	defer m.stateMu.RUnlock()
+
+	return append([]EffectiveVariable(nil), m.effectiveVariablesList...)
+}
+
+// GetLastPreparedAt returns time of last successful Do call, zero time if config was not prepared...
+func (m *configManager) GetLastPreparedAt() time.Time {
+	m.stateMu.RLock()
+	defer m.stateMu.RUnlock()
+
+	return m.lastPreparedAt
+}
+
 func (m *configManager) PrepareTo(targetForPrepare interface{}) *configManager {
 	wrappedTargetConf := &targetConfigWrapper{
 		e:                   m.e,
@@ -127,28 +175,54 @@ func (m *configManager) PrepareTo(targetForPrepare interface{}) *configManager {
 	return m
 }
 
-func (m *configManager) Do(_ context.Context) error {
+func (m *configManager) Do(ctx context.Context) error {
 	cfgVarPool := newConfigVarsPool(m.e, m.secretsSrv, m.wrapperConfig.TargetForPrepare,
 		m.wrapperConfig.dependentCfgSrvList)
 
-	err := cfgVarPool.Process()
+	err := cfgVarPool.Process(ctx)
 	if err != nil {
 		return m.e.ErrorNoWrap(err)
 	}
 
-	err = cfgVarPool.ClearENV()
+	clearENVReport, err := cfgVarPool.ClearENV(m.clearENVPolicy)
 	if err != nil {
 		return m.e.ErrorNoWrap(err)
 	}
 
+	effectiveVariablesList := cfgVarPool.GetEffectiveVariables()
+
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+
+	m.clearENVReport = clearENVReport
+	m.effectiveVariablesList = effectiveVariablesList
+	m.lastPreparedAt = time.Now()
+
 	return nil
 }
 
+// errFormatterOrDefault returns passed error formatter or standard library formatter, if passed formatter is nil...
+func errFormatterOrDefault(errFmtSvc errorFormatterService) errorFormatterService {
+	if errFmtSvc == nil {
+		return errfmt.NewStdFormatter()
+	}
+
+	return errFmtSvc
+}
+
+// NewConfigManager - create config manager. errFmtSvc is optional - standard library formatter
+// will be used if nil passed...
 func NewConfigManager(errFmtSvc errorFormatterService) *configManager {
 	return &configManager{
-		e:             errFmtSvc,
-		secretsSrv:    nil,
-		wrapperConfig: nil,
+		e:              errFormatterOrDefault(errFmtSvc),
+		secretsSrv:     nil,
+		wrapperConfig:  nil,
+		clearENVPolicy: ClearENVPolicySecrets,
+
+		stateMu:                sync.RWMutex{},
+		clearENVReport:         nil, // will be filled after Do call
+		effectiveVariablesList: nil, // will be filled after Do call
+		lastPreparedAt:         time.Time{},
 	}
 }
 
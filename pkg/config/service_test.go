@@ -34,6 +34,7 @@ package config
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strconv"
 	"testing"
@@ -72,7 +73,6 @@ func newMockLdFlagManager(releaseTag string,
 
 	return &ldFlagManager{
 		buildDateAt:   buildTime,
-		buildDateTS:   uint64(buildTime.Unix()),
 		releaseTag:    releaseTag,
 		commitID:      commitID,
 		shortCommitID: shortCommitID,
@@ -166,3 +166,19 @@ func TestBaseEnvVariablesPrepareWith(t *testing.T) {
 		t.Errorf("not equal StageName")
 	}
 }
+
+func TestConfigManagerDefaultErrFormatter(t *testing.T) {
+	type requiredConfig struct {
+		Value string `envconfig:"DEFAULT_ERR_FMT_REQUIRED_VALUE" required:"true"`
+	}
+
+	err := NewConfigManager(nil).PrepareTo(&requiredConfig{}).Do(context.Background())
+	if !errors.Is(err, ErrVariableEmptyButRequired) {
+		t.Errorf("error must be wrapped by default formatter: %v", err)
+	}
+
+	err = NewConfigManager(nil).PrepareTo(&BaseConfig{}).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+	}
+}
@@ -0,0 +1,380 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrWrongByteSize    = errors.New("wrong byte size")
+	ErrByteSizeOverflow = errors.New("byte size overflows uint64")
+	ErrWrongPercent     = errors.New("wrong percent")
+	ErrWrongRate        = errors.New("wrong rate")
+)
+
+// ByteSize - size in bytes. Parsed from value with SI or IEC suffix, e.g. "4MiB", "1.5GB", "512 KiB", "100B".
+// Suffixes are case-insensitive, value without suffix is a count of bytes...
+type ByteSize uint64
+
+const (
+	KiB ByteSize = 1 << (10 * (iota + 1))
+	MiB
+	GiB
+	TiB
+	PiB
+	EiB
+)
+
+const (
+	KB ByteSize = 1000
+	MB          = KB * 1000
+	GB          = MB * 1000
+	TB          = GB * 1000
+	PB          = TB * 1000
+	EB          = PB * 1000
+)
+
+// byteSizeUnit - suffix and multiplier of byte size...
+type byteSizeUnit struct {
+	suffix     string
+	multiplier uint64
+}
+
+// byteSizeUnits - units of byte size, IEC units are first - they are preferred by String function...
+var byteSizeUnits = []byteSizeUnit{
+	{suffix: "EiB", multiplier: uint64(EiB)},
+	{suffix: "PiB", multiplier: uint64(PiB)},
+	{suffix: "TiB", multiplier: uint64(TiB)},
+	{suffix: "GiB", multiplier: uint64(GiB)},
+	{suffix: "MiB", multiplier: uint64(MiB)},
+	{suffix: "KiB", multiplier: uint64(KiB)},
+	{suffix: "EB", multiplier: uint64(EB)},
+	{suffix: "PB", multiplier: uint64(PB)},
+	{suffix: "TB", multiplier: uint64(TB)},
+	{suffix: "GB", multiplier: uint64(GB)},
+	{suffix: "MB", multiplier: uint64(MB)},
+	{suffix: "KB", multiplier: uint64(KB)},
+	{suffix: "B", multiplier: 1},
+}
+
+// ParseByteSize - parse byte size with SI or IEC suffix. Fractional values are allowed,
+// if they are a whole count of bytes, e.g. "1.5KiB"...
+func ParseByteSize(value string) (ByteSize, error) {
+	rawNumber, suffix := splitNumberAndUnit(value)
+
+	multiplier := uint64(1)
+
+	if suffix != "" {
+		isKnownSuffix := false
+
+		for _, unit := range byteSizeUnits {
+			if strings.EqualFold(unit.suffix, suffix) {
+				multiplier, isKnownSuffix = unit.multiplier, true
+
+				break
+			}
+		}
+
+		if !isKnownSuffix {
+			return 0, fmt.Errorf("%w: unknown suffix: %s", ErrWrongByteSize, value)
+		}
+	}
+
+	// big.Rat also accepts fractions and exponents, they aren't allowed for byte sizes
+	if rawNumber == "" || strings.ContainsAny(rawNumber, "/eE+-") {
+		return 0, fmt.Errorf("%w: %s", ErrWrongByteSize, value)
+	}
+
+	number, isValid := new(big.Rat).SetString(rawNumber)
+	if !isValid {
+		return 0, fmt.Errorf("%w: %s", ErrWrongByteSize, value)
+	}
+
+	number.Mul(number, new(big.Rat).SetUint64(multiplier))
+
+	if !number.IsInt() {
+		return 0, fmt.Errorf("%w: fractional count of bytes: %s", ErrWrongByteSize, value)
+	}
+
+	if !number.Num().IsUint64() {
+		return 0, fmt.Errorf("%w: %s", ErrByteSizeOverflow, value)
+	}
+
+	return ByteSize(number.Num().Uint64()), nil
+}
+
+// String returns byte size with largest unit, which divides size without remainder. IEC units are preferred...
+func (s ByteSize) String() string {
+	if s == 0 {
+		return "0B"
+	}
+
+	for _, unit := range byteSizeUnits {
+		if uint64(s)%unit.multiplier == 0 {
+			return strconv.FormatUint(uint64(s)/unit.multiplier, 10) + unit.suffix
+		}
+	}
+
+	return strconv.FormatUint(uint64(s), 10) + "B"
+}
+
+func (s *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = size
+
+	return nil
+}
+
+func (s ByteSize) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalJSON - parse JSON string with suffix or JSON number - count of bytes...
+func (s *ByteSize) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, s)
+}
+
+// Percent - value in percents, e.g. "115%" or "2.5%". Value without "%" suffix is also a value in percents...
+type Percent float64
+
+// ParsePercent - parse value in percents with optional "%" suffix...
+func ParsePercent(value string) (Percent, error) {
+	rawNumber := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "%"))
+
+	number, err := strconv.ParseFloat(rawNumber, 64)
+	if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+		return 0, fmt.Errorf("%w: %s", ErrWrongPercent, value)
+	}
+
+	return Percent(number), nil
+}
+
+// Ratio returns percent value as fraction of one, e.g. 1.15 for 115%...
+func (p Percent) Ratio() float64 {
+	return float64(p) / 100 //nolint:mnd // it's ok, percents in one
+}
+
+// String returns value with "%" suffix, e.g. "115%"...
+func (p Percent) String() string {
+	return strconv.FormatFloat(float64(p), 'f', -1, 64) + "%"
+}
+
+func (p *Percent) UnmarshalText(text []byte) error {
+	percent, err := ParsePercent(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = percent
+
+	return nil
+}
+
+func (p Percent) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON - parse JSON string with optional "%" suffix or JSON number...
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, p)
+}
+
+// Rate - count of events per time interval, e.g. "50/s", "1000/h", "5/100ms" or "0.5/min".
+// Interval is a duration or unit: ns, us, ms, s, sec, m, min, h, hour, d, day...
+type Rate struct {
+	Count float64
+	Per   time.Duration
+}
+
+const day = 24 * time.Hour
+
+// rateUnits - named intervals of rate, which are not supported by time.ParseDuration...
+var rateUnits = map[string]time.Duration{
+	"sec":  time.Second,
+	"min":  time.Minute,
+	"hour": time.Hour,
+	"d":    day,
+	"day":  day,
+}
+
+// rateShortUnits - intervals printed by String function as units...
+var rateShortUnits = map[time.Duration]string{
+	time.Second: "s",
+	time.Minute: "m",
+	time.Hour:   "h",
+	day:         "d",
+}
+
+// ParseRate - parse rate in format <count>/<interval>. Count can be fractional, interval must be positive...
+func ParseRate(value string) (Rate, error) {
+	rawCount, rawInterval, isFound := strings.Cut(strings.TrimSpace(value), "/")
+	if !isFound {
+		return Rate{}, fmt.Errorf("%w: interval is not defined: %s", ErrWrongRate, value)
+	}
+
+	count, err := strconv.ParseFloat(strings.TrimSpace(rawCount), 64)
+	if err != nil || count < 0 || math.IsInf(count, 0) || math.IsNaN(count) {
+		return Rate{}, fmt.Errorf("%w: wrong count: %s", ErrWrongRate, value)
+	}
+
+	rawInterval = strings.TrimSpace(rawInterval)
+
+	interval, isUnit := rateUnits[strings.ToLower(rawInterval)]
+	if !isUnit {
+		if rawInterval != "" && !unicode.IsDigit(rune(rawInterval[0])) {
+			rawInterval = "1" + rawInterval
+		}
+
+		interval, err = time.ParseDuration(rawInterval)
+		if err != nil || interval <= 0 {
+			return Rate{}, fmt.Errorf("%w: wrong interval: %s", ErrWrongRate, value)
+		}
+	}
+
+	return Rate{Count: count, Per: interval}, nil
+}
+
+// PerSecond returns count of events per second...
+func (r Rate) PerSecond() float64 {
+	if r.Per <= 0 {
+		return 0
+	}
+
+	return r.Count / r.Per.Seconds()
+}
+
+// Interval returns interval between events, zero for zero rate...
+func (r Rate) Interval() time.Duration {
+	if r.Count <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(r.Per) / r.Count)
+}
+
+// String returns rate in format <count>/<interval>, e.g. "50/s" or "5/100ms". Zero rate is returned
+// as empty string, which is parsed back by UnmarshalText function...
+func (r Rate) String() string {
+	if r == (Rate{}) {
+		return ""
+	}
+
+	interval, isShortUnit := rateShortUnits[r.Per]
+	if !isShortUnit {
+		interval = r.Per.String()
+	}
+
+	return strconv.FormatFloat(r.Count, 'f', -1, 64) + "/" + interval
+}
+
+// UnmarshalText - parse rate by ParseRate function, empty text is a zero rate...
+func (r *Rate) UnmarshalText(text []byte) error {
+	if strings.TrimSpace(string(text)) == "" {
+		*r = Rate{}
+
+		return nil
+	}
+
+	rate, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+
+	return nil
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON - parse JSON string with rate...
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, r)
+}
+
+// textUnmarshaler - encoding.TextUnmarshaler interface of value types...
+type textUnmarshaler interface {
+	UnmarshalText(text []byte) error
+}
+
+// unmarshalJSONText - unmarshal JSON string or JSON number by UnmarshalText function. Null is ignored...
+func unmarshalJSONText(data []byte, target textUnmarshaler) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var text string
+
+	err := json.Unmarshal(data, &text)
+	if err != nil {
+		var number json.Number
+
+		numberErr := json.Unmarshal(data, &number)
+		if numberErr != nil {
+			return err //nolint:wrapcheck // it's ok, json error
+		}
+
+		text = number.String()
+	}
+
+	return target.UnmarshalText([]byte(text))
+}
+
+// splitNumberAndUnit - split value to number and unit suffix, spaces between them are allowed...
+func splitNumberAndUnit(value string) (string, string) {
+	value = strings.TrimSpace(value)
+
+	unitIndex := strings.IndexFunc(value, func(symbol rune) bool {
+		return unicode.IsLetter(symbol) || unicode.IsSpace(symbol)
+	})
+	if unitIndex < 0 {
+		return value, ""
+	}
+
+	return value[:unitIndex], strings.TrimSpace(value[unitIndex:])
+}
@@ -0,0 +1,275 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+type valueTypesConfig struct {
+	MaxBlockSize       ByteSize `envconfig:"VALUE_TYPES_MAX_BLOCK_SIZE" default:"1MiB"`
+	GasPriceMultiplier Percent  `envconfig:"VALUE_TYPES_GAS_PRICE_MULTIPLIER" default:"100%"`
+	RPCRate            Rate     `envconfig:"VALUE_TYPES_RPC_RATE" default:"10/s"`
+	BurstRate          *Rate    `envconfig:"VALUE_TYPES_BURST_RATE" default:"5/100ms"`
+}
+
+func TestParseByteSize(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected ByteSize
+		err      error
+	}{
+		{value: "4MiB", expected: 4 * MiB, err: nil},
+		{value: "4mib", expected: 4 * MiB, err: nil},
+		{value: "512 KiB", expected: 512 * KiB, err: nil},
+		{value: "1.5GB", expected: 1500 * MB, err: nil},
+		{value: "1.5KiB", expected: 1536, err: nil},
+		{value: "100", expected: 100, err: nil},
+		{value: "100B", expected: 100, err: nil},
+		{value: "16EiB", expected: 0, err: ErrByteSizeOverflow},
+		{value: "18446744073709551616", expected: 0, err: ErrByteSizeOverflow},
+		{value: "1.1B", expected: 0, err: ErrWrongByteSize},
+		{value: "-1KB", expected: 0, err: ErrWrongByteSize},
+		{value: "1e3", expected: 0, err: ErrWrongByteSize},
+		{value: "1/2KiB", expected: 0, err: ErrWrongByteSize},
+		{value: "10XB", expected: 0, err: ErrWrongByteSize},
+		{value: "MiB", expected: 0, err: ErrWrongByteSize},
+	}
+
+	for _, testCase := range testCases {
+		size, err := ParseByteSize(testCase.value)
+		if !errors.Is(err, testCase.err) || size != testCase.expected {
+			t.Errorf("%s: not equal byte size %d or error: %v", testCase.value, size, err)
+		}
+	}
+}
+
+func TestValueTypesString(t *testing.T) {
+	for _, size := range []ByteSize{0, 100, 4 * MiB, 1500 * MB, 1536, ^ByteSize(0)} {
+		parsedSize, err := ParseByteSize(size.String())
+		if err != nil || parsedSize != size {
+			t.Errorf("byte size %d is not round-trippable: %s", size, size.String())
+		}
+	}
+
+	if (4*MiB).String() != "4MiB" || (1500*MB).String() != "1500MB" || (3*GB).String() != "3GB" {
+		t.Errorf("not equal string of byte size")
+	}
+
+	for _, percent := range []Percent{115, 2.5, 0, -10} {
+		parsedPercent, err := ParsePercent(percent.String())
+		if err != nil || parsedPercent != percent {
+			t.Errorf("percent %f is not round-trippable: %s", percent, percent.String())
+		}
+	}
+
+	for _, rate := range []Rate{{Count: 50, Per: time.Second}, {Count: 0.5, Per: time.Minute},
+		{Count: 5, Per: 100 * time.Millisecond}, {Count: 1000, Per: 24 * time.Hour}} {
+		parsedRate, err := ParseRate(rate.String())
+		if err != nil || parsedRate != rate {
+			t.Errorf("rate %+v is not round-trippable: %s", rate, rate.String())
+		}
+	}
+
+	zeroRate := Rate{Count: 5, Per: time.Second}
+
+	err := zeroRate.UnmarshalText([]byte(Rate{}.String()))
+	if err != nil || zeroRate != (Rate{}) {
+		t.Errorf("zero rate is not round-trippable: %+v, %v", zeroRate, err)
+	}
+}
+
+func TestParsePercentAndRate(t *testing.T) {
+	percent, err := ParsePercent(" 115% ")
+	if err != nil || percent != 115 || percent.Ratio() != 1.15 {
+		t.Errorf("not equal percent: %v, %v", percent, err)
+	}
+
+	for _, value := range []string{"", "%", "ten%", "NaN%", "Inf"} {
+		_, err = ParsePercent(value)
+		if !errors.Is(err, ErrWrongPercent) {
+			t.Errorf("%s: expected wrong percent error: %v", value, err)
+		}
+	}
+
+	testCases := []struct {
+		value    string
+		expected Rate
+	}{
+		{value: "50/s", expected: Rate{Count: 50, Per: time.Second}},
+		{value: "50 / sec", expected: Rate{Count: 50, Per: time.Second}},
+		{value: "0.5/min", expected: Rate{Count: 0.5, Per: time.Minute}},
+		{value: "1000/Hour", expected: Rate{Count: 1000, Per: time.Hour}},
+		{value: "5/100ms", expected: Rate{Count: 5, Per: 100 * time.Millisecond}},
+		{value: "3/d", expected: Rate{Count: 3, Per: 24 * time.Hour}},
+	}
+
+	for _, testCase := range testCases {
+		rate, rateErr := ParseRate(testCase.value)
+		if rateErr != nil || rate != testCase.expected {
+			t.Errorf("%s: not equal rate %+v: %v", testCase.value, rate, rateErr)
+		}
+	}
+
+	for _, value := range []string{"50", "-1/s", "x/s", "1/", "1/0s", "1/-1s", "1/week"} {
+		_, err = ParseRate(value)
+		if !errors.Is(err, ErrWrongRate) {
+			t.Errorf("%s: expected wrong rate error: %v", value, err)
+		}
+	}
+
+	rate := Rate{Count: 50, Per: time.Second}
+	if rate.PerSecond() != 50 || rate.Interval() != 20*time.Millisecond {
+		t.Errorf("not equal rate per second or interval")
+	}
+}
+
+func TestValueTypesJSON(t *testing.T) {
+	cfg := struct {
+		MaxBlockSize ByteSize `json:"max_block_size"`
+		RawSize      ByteSize `json:"raw_size"`
+		Multiplier   Percent  `json:"multiplier"`
+		RPCRate      Rate     `json:"rpc_rate"`
+	}{}
+
+	err := json.Unmarshal([]byte(`{"max_block_size": "4MiB", "raw_size": 1024, "multiplier": 115,
+		"rpc_rate": "50/s"}`), &cfg)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.MaxBlockSize != 4*MiB || cfg.RawSize != KiB || cfg.Multiplier != 115 ||
+		cfg.RPCRate != (Rate{Count: 50, Per: time.Second}) {
+		t.Errorf("not equal decoded config: %+v", cfg)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil || string(data) !=
+		`{"max_block_size":"4MiB","raw_size":"1KiB","multiplier":"115%","rpc_rate":"50/s"}` {
+		t.Errorf("not equal encoded config: %s, %v", data, err)
+	}
+
+	err = json.Unmarshal([]byte(`{"max_block_size": "16EiB"}`), &cfg)
+	if !errors.Is(err, ErrByteSizeOverflow) {
+		t.Errorf("expected overflow error: %v", err)
+	}
+}
+
+func TestValueTypesSetField(t *testing.T) {
+	defer os.Unsetenv("VALUE_TYPES_MAX_BLOCK_SIZE")
+	defer os.Unsetenv("VALUE_TYPES_GAS_PRICE_MULTIPLIER")
+	defer os.Unsetenv("VALUE_TYPES_RPC_RATE")
+
+	_ = os.Setenv("VALUE_TYPES_MAX_BLOCK_SIZE", "4MiB")
+	_ = os.Setenv("VALUE_TYPES_GAS_PRICE_MULTIPLIER", "115%")
+	_ = os.Setenv("VALUE_TYPES_RPC_RATE", "50/s")
+
+	secretManager := &mockSecretManager{ValuesPool: map[string]string{}}
+	cfg := &valueTypesConfig{}
+
+	err := NewConfigManager(common.NewMockErrFormatter()).PrepareTo(cfg).
+		With(secretManager).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.MaxBlockSize != 4*MiB || cfg.GasPriceMultiplier != 115 ||
+		cfg.RPCRate != (Rate{Count: 50, Per: time.Second}) ||
+		cfg.BurstRate == nil || *cfg.BurstRate != (Rate{Count: 5, Per: 100 * time.Millisecond}) {
+		t.Errorf("not equal filled config: %+v", cfg)
+	}
+
+	_ = os.Setenv("VALUE_TYPES_MAX_BLOCK_SIZE", "16EiB")
+
+	err = NewConfigManager(nil).PrepareTo(&valueTypesConfig{}).With(secretManager).Do(context.Background())
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrByteSizeOverflow) {
+		t.Errorf("expected parse error with overflow: %v", err)
+	}
+}
+
+func TestSetFieldStandardTypes(t *testing.T) {
+	var (
+		timestamp    time.Time
+		timeout      time.Duration
+		timeoutsList []time.Duration
+	)
+
+	err := common.SetField("2024-10-09T12:30:00Z", reflect.ValueOf(&timestamp).Elem())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if !timestamp.Equal(time.Date(2024, 10, 9, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("not equal timestamp: %s", timestamp)
+	}
+
+	err = common.SetField("09.10.2024", reflect.ValueOf(&timestamp).Elem())
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("expected parse error of timestamp: %v", err)
+	}
+
+	err = common.SetField("1m30s", reflect.ValueOf(&timeout).Elem())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if timeout != 90*time.Second {
+		t.Errorf("not equal timeout: %s", timeout)
+	}
+
+	err = common.SetField("1s,250ms", reflect.ValueOf(&timeoutsList).Elem())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if len(timeoutsList) != 2 || timeoutsList[0] != time.Second || timeoutsList[1] != 250*time.Millisecond {
+		t.Errorf("not equal timeouts list: %v", timeoutsList)
+	}
+}
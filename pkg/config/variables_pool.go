@@ -33,12 +33,16 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
 )
 
 var (
@@ -47,16 +51,27 @@ var (
 	ErrVariableEmptyButRequired         = errors.New("variables is empty and has required tag")
 )
 
+const (
+	envSourceName     = "env"
+	defaultSourceName = "default"
+	secretSourceName  = "secret"
+)
+
 var _ configVariablesPoolService = (*configVariablesPool)(nil)
 
 type configVariablesPool struct {
 	e                     errorFormatterService
 	targetConfigSvc       interface{}
-	secretsDataSvc        secretManagerService
+	secretsDataSvc        secretProviderService
 	dependenciesSvc       []interface{}
+	variableSourcesList   []variableSourceService
+	logger                *common.Logger
+	environmentNames      map[string]string
+	environmentName       string
 	envVariablesNameList  []string
 	envVariablesList      []common.Field
 	secretVariablesList   []common.Field
+	resolvedSecretsMap    map[string]secrets.Secret
 	envVariablesNameCount uint16
 	secretVariablesCount  uint16
 }
@@ -85,8 +100,29 @@ func (u *configVariablesPool) addEnvVariable(variable common.Field) error {
 	return nil
 }
 
-func (u *configVariablesPool) Process() error {
-	err := u.processFields(u.targetConfigSvc)
+// Process - fill target config by values of variable sources, process environment and secret provider.
+// Context passed to secret provider calls, processing stops on context cancellation...
+func (u *configVariablesPool) Process(ctx context.Context) error {
+	targetValue := reflect.ValueOf(u.targetConfigSvc)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return u.e.ErrorOnly(ErrPassedStructMustBeAPointer)
+	}
+
+	u.environmentName = u.resolveEnvironmentName()
+
+	err := u.checkVersionConstraint()
+	if err != nil {
+		return u.e.ErrorNoWrap(err)
+	}
+
+	rootPath := targetValue.Elem().Type().Name()
+
+	err = u.resolveSecrets(ctx, u.discoverSecretFields(reflect.TypeOf(u.targetConfigSvc), rootPath))
+	if err != nil {
+		return u.e.ErrorNoWrap(err)
+	}
+
+	err = u.processFields(ctx, u.targetConfigSvc, rootPath)
 	if err != nil {
 		return u.e.ErrorNoWrap(err)
 	}
@@ -99,7 +135,12 @@ func (u *configVariablesPool) Process() error {
 // TODO: refactor it - separate by sub-function
 //
 //nolint:funlen,gocognit,gocyclo,cyclop // it's ok. Need to refactor this function, but now - it's ok.
-func (u *configVariablesPool) processFields(target interface{}) error {
+func (u *configVariablesPool) processFields(ctx context.Context, target interface{}, path string) error {
+	err := ctx.Err()
+	if err != nil {
+		return u.e.ErrorOnly(err, path)
+	}
+
 	targetSource := reflect.ValueOf(target)
 
 	// must be a pointer
@@ -113,7 +154,10 @@ func (u *configVariablesPool) processFields(target interface{}) error {
 
 	castedInitConfigField, isPossibleToCast := element.Addr().Interface().(configInitService)
 	if isPossibleToCast {
+		startedAt := time.Now()
 		prepErr := castedInitConfigField.InitWith(u.dependenciesSvc...)
+		u.logger.HookFinished(path, "InitWith", time.Since(startedAt), prepErr)
+
 		if prepErr != nil {
 			return u.e.ErrorOnly(prepErr)
 		}
@@ -123,6 +167,7 @@ func (u *configVariablesPool) processFields(target interface{}) error {
 	numFields := elemType.NumField()
 	for i := range numFields {
 		structFieldInfo := elemType.Field(i) // struct field info
+		fieldPath := path + "." + structFieldInfo.Name
 
 		fieldValue := element.Field(i) // reflect.RfValue
 		if !fieldValue.CanSet() {
@@ -148,98 +193,136 @@ func (u *configVariablesPool) processFields(target interface{}) error {
 			fieldValue = fieldValue.Elem()
 		}
 
-		// recursively process nested struct
-		if fieldValue.Kind() == reflect.Struct && fieldValue.CanInterface() {
-			processErr := u.processFields(fieldValue.Addr().Interface())
+		// recursively process nested struct, structs with UnmarshalText function are single values
+		if fieldValue.Kind() == reflect.Struct && fieldValue.CanInterface() &&
+			!common.IsTextUnmarshaler(fieldValue.Type()) {
+			processErr := u.processFields(ctx, fieldValue.Addr().Interface(), fieldPath)
 			if processErr != nil {
-				return u.e.ErrorOnly(processErr)
+				return u.e.ErrorNoWrap(processErr)
 			}
 
 			continue
 		}
 
+		envConfigKey := structFieldInfo.Tag.Get(common.TagEnvconfig)
+
 		var isSecret = false
 
 		boolVarSrt, isTagExists := structFieldInfo.Tag.Lookup(common.TagSecret)
 		if isTagExists {
 			boolVar, err := strconv.ParseBool(boolVarSrt)
 			if err != nil {
-				return u.e.ErrorOnly(err)
+				return u.e.ErrorNoWrap(common.NewFieldError(
+					&common.ParseError{Cause: err, Value: boolVarSrt, Type: "bool"},
+					fieldPath, newTagsField(structFieldInfo, envConfigKey), false))
 			}
 
 			isSecret = boolVar
 		}
 
-		var isRequired = false
-
-		boolVarSrt, isTagExists = structFieldInfo.Tag.Lookup(common.TagRequired)
-		if isTagExists {
-			boolVar, err := strconv.ParseBool(boolVarSrt)
-			if err != nil {
-				return u.e.ErrorOnly(err)
-			}
-
-			isRequired = boolVar
+		isRequired, err := common.IsRequired(structFieldInfo.Tag, u.environmentName, u.environmentNames)
+		if err != nil {
+			return u.e.ErrorNoWrap(common.NewFieldError(err, fieldPath,
+				newTagsField(structFieldInfo, envConfigKey), false))
 		}
 
 		if isSecret {
-			envConfigKey := structFieldInfo.Tag.Get(common.TagEnvconfig)
+			reference, refErr := u.lookupSecretReference(structFieldInfo.Tag, envConfigKey)
 
-			value, isExists := u.secretsDataSvc.GetByName(envConfigKey)
-			if !isExists && isRequired {
-				return u.e.ErrorOnly(ErrVariableEmptyButRequired, structFieldInfo.Name)
+			commonField := common.Field{
+				Name:      structFieldInfo.Name,
+				EnvKey:    envConfigKey,
+				SecretKey: reference.Key(),
+				Source:    secretSourceName,
+				RfValue:   fieldValue,
+				RfTags:    structFieldInfo.Tag,
+				Value:     "",
 			}
 
-			commonField := common.Field{
-				Name:    structFieldInfo.Name,
-				RfValue: fieldValue,
-				RfTags:  structFieldInfo.Tag,
-				Value:   value,
+			if refErr != nil {
+				return u.e.ErrorNoWrap(common.NewFieldError(&common.ValidationError{
+					Cause: refErr,
+					Rule:  common.ValidationRuleSecretFormat,
+				}, fieldPath, commonField, true))
+			}
+
+			secret, isExists := reference.Lookup(u.resolvedSecretsMap)
+			u.logger.SecretFetched(fieldPath, commonField.SecretKey, u.secretsDataSvc, isExists)
+
+			value := secret.Value
+			commonField.Value = value
+
+			if !isExists && isRequired {
+				return u.e.ErrorNoWrap(common.NewFieldError(&common.ValidationError{
+					Cause: ErrVariableEmptyButRequired,
+					Rule:  common.ValidationRuleRequired,
+				}, fieldPath, commonField, true))
 			}
 
 			addErr := u.addSecretVariable(commonField)
 			if addErr != nil {
-				return u.e.ErrorOnly(addErr)
+				return u.e.ErrorNoWrap(common.NewFieldError(addErr, fieldPath, commonField, true))
 			}
 
+			u.logger.FieldResolved(fieldPath, envConfigKey, secretSourceName, value, true)
+
 			continue
 		}
 
-		envConfigKey := structFieldInfo.Tag.Get(common.TagEnvconfig)
-
-		value, isEnvVariableExists := os.LookupEnv(envConfigKey)
+		value, sourceName, isEnvVariableExists := u.lookupVariable(envConfigKey)
 		if !isEnvVariableExists && isRequired {
-			return u.e.ErrorOnly(ErrVariableEmptyButRequired, structFieldInfo.Name)
+			return u.e.ErrorNoWrap(common.NewFieldError(&common.ValidationError{
+				Cause: ErrVariableEmptyButRequired,
+				Rule:  common.ValidationRuleRequired,
+			}, fieldPath, newTagsField(structFieldInfo, envConfigKey), false))
 		}
 
-		defaultValue, hasDefaultValue := structFieldInfo.Tag.Lookup(common.TagDefault)
+		defaultValue, hasDefaultValue := common.LookupDefault(structFieldInfo.Tag,
+			u.environmentName, u.environmentNames)
 		if !isEnvVariableExists && hasDefaultValue {
 			value = defaultValue
+			sourceName = defaultSourceName
 		}
 
 		commonField := common.Field{
-			Name:    structFieldInfo.Name,
-			RfValue: fieldValue,
-			RfTags:  structFieldInfo.Tag,
-			Value:   value,
+			Name:      structFieldInfo.Name,
+			EnvKey:    envConfigKey,
+			SecretKey: "",
+			Source:    sourceName,
+			RfValue:   fieldValue,
+			RfTags:    structFieldInfo.Tag,
+			Value:     value,
 		}
 
 		addErr := u.addEnvVariable(commonField)
 		if addErr != nil {
-			return u.e.ErrorOnly(addErr)
+			return u.e.ErrorNoWrap(common.NewFieldError(addErr, fieldPath, commonField, false))
 		}
+
+		isSensitive := isSensitiveField(commonField)
+		if sourceName == defaultSourceName {
+			u.logger.DefaultApplied(fieldPath, envConfigKey, value, isSensitive)
+		}
+
+		u.logger.FieldResolved(fieldPath, envConfigKey, sourceName, value, isSensitive)
 	}
 
 	castedField, isPossibleToCast := element.Addr().Interface().(dependentConfigService)
 	if isPossibleToCast {
 		if u.dependenciesSvc != nil {
+			startedAt := time.Now()
 			prepErr := castedField.PrepareWith(u.dependenciesSvc...)
+			u.logger.HookFinished(path, "PrepareWith", time.Since(startedAt), prepErr)
+
 			if prepErr != nil {
 				return u.e.ErrorOnly(prepErr)
 			}
 		}
 
+		startedAt := time.Now()
 		prepErr := castedField.Prepare()
+		u.logger.HookFinished(path, "Prepare", time.Since(startedAt), prepErr)
+
 		if prepErr != nil {
 			return u.e.ErrorOnly(prepErr)
 		}
@@ -249,7 +332,10 @@ func (u *configVariablesPool) processFields(target interface{}) error {
 
 	castedConfigField, isPossibleToCast := element.Addr().Interface().(configService)
 	if isPossibleToCast {
+		startedAt := time.Now()
 		prepErr := castedConfigField.Prepare()
+		u.logger.HookFinished(path, "Prepare", time.Since(startedAt), prepErr)
+
 		if prepErr != nil {
 			return u.e.ErrorOnly(prepErr)
 		}
@@ -258,30 +344,250 @@ func (u *configVariablesPool) processFields(target interface{}) error {
 	return nil
 }
 
-func (u *configVariablesPool) ClearENV() error {
-	for i := uint16(0); i != u.envVariablesNameCount; i++ {
-		envField := u.envVariablesList[i]
+// newTagsField - create field description without value, e.g. for errors of field tags processing...
+func newTagsField(structField reflect.StructField, envKey string) common.Field {
+	return common.Field{
+		Name:      structField.Name,
+		EnvKey:    envKey,
+		SecretKey: "",
+		Source:    "",
+		RfValue:   reflect.Value{},
+		RfTags:    structField.Tag,
+		Value:     "",
+	}
+}
+
+// resolveEnvironmentName - resolve canonical application environment name by dependencies list or by
+// APP_ENV variable. If APP_ENV variable is not defined, default environment of BaseConfig - development, is used.
+// Environment name used for selection of environment-specific defaults and required_in tags...
+func (u *configVariablesPool) resolveEnvironmentName() string {
+	for _, dependencySvc := range u.dependenciesSvc {
+		castedEnvNameSvc, isPossibleToCast := dependencySvc.(environmentNameProviderService)
+		if !isPossibleToCast {
+			continue
+		}
+
+		canonicalName, isKnown := u.environmentNames[castedEnvNameSvc.GetEnvironmentName()]
+		if isKnown {
+			return canonicalName
+		}
+	}
+
+	value, _, isExists := u.lookupVariable(AppEnvironmentNameVariable)
+	if !isExists {
+		value = DefaultEnvironmentName
+	}
+
+	return u.environmentNames[value]
+}
+
+// checkVersionConstraint - check release version of application binary by APP_CONFIG_MIN_VERSION and
+// APP_CONFIG_MAX_VERSION variables. Release version provided by ldflag manager or BaseConfig in dependencies list...
+func (u *configVariablesPool) checkVersionConstraint() error {
+	minVersion, _, _ := u.lookupVariable(AppConfigMinVersionVariable)
+	maxVersion, _, _ := u.lookupVariable(AppConfigMaxVersionVariable)
+
+	if minVersion == "" && maxVersion == "" {
+		return nil
+	}
+
+	for _, dependencySvc := range u.dependenciesSvc {
+		castedVersionSvc, isPossibleToCast := dependencySvc.(releaseVersionProviderService)
+		if !isPossibleToCast {
+			continue
+		}
+
+		releaseVersion, err := castedVersionSvc.GetReleaseVersion()
+		if errors.Is(err, ErrReleaseVersionIsNotDefined) {
+			continue
+		}
+
+		if err != nil {
+			return u.e.ErrorNoWrap(err)
+		}
 
-		err := os.Unsetenv(envField.Name)
+		err = common.CheckVersionConstraint(releaseVersion, minVersion, maxVersion)
 		if err != nil {
-			return u.e.ErrorOnly(err)
+			return u.e.ErrorNoWrap(err)
 		}
+
+		return nil
+	}
+
+	return u.e.ErrorOnly(ErrReleaseVersionIsNotDefined, AppConfigMinVersionVariable, AppConfigMaxVersionVariable)
+}
+
+// discoverSecretFields - discovery pass of config struct type. Collects secret-tagged fields of all nested structs
+// in order of fields declaration. Fields with invalid tags are skipped - they will be reported by processFields...
+func (u *configVariablesPool) discoverSecretFields(targetType reflect.Type, path string) []secrets.FieldReference {
+	return u.collectSecretFields(targetType, path, make(map[reflect.Type]bool), make([]secrets.FieldReference, 0))
+}
+
+func (u *configVariablesPool) collectSecretFields(targetType reflect.Type,
+	path string,
+	visitedTypes map[reflect.Type]bool,
+	refsList []secrets.FieldReference,
+) []secrets.FieldReference {
+	for targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+
+	// recursive types are not supported by processFields, so they are not walked twice
+	if targetType.Kind() != reflect.Struct || visitedTypes[targetType] {
+		return refsList
 	}
 
+	visitedTypes[targetType] = true
+	defer delete(visitedTypes, targetType)
+
+	for i := range targetType.NumField() {
+		structFieldInfo := targetType.Field(i)
+		fieldPath := path + "." + structFieldInfo.Name
+
+		if !structFieldInfo.IsExported() {
+			continue
+		}
+
+		isIgnored, _ := strconv.ParseBool(structFieldInfo.Tag.Get(common.TagIgnored))
+		if isIgnored {
+			continue
+		}
+
+		fieldType := structFieldInfo.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && !common.IsTextUnmarshaler(fieldType) {
+			refsList = u.collectSecretFields(fieldType, fieldPath, visitedTypes, refsList)
+
+			continue
+		}
+
+		isSecret, _ := strconv.ParseBool(structFieldInfo.Tag.Get(common.TagSecret))
+		if !isSecret {
+			continue
+		}
+
+		envConfigKey := structFieldInfo.Tag.Get(common.TagEnvconfig)
+
+		reference, err := u.lookupSecretReference(structFieldInfo.Tag, envConfigKey)
+		if err != nil {
+			continue
+		}
+
+		refsList = append(refsList, secrets.FieldReference{
+			Field: common.Field{
+				Name:      structFieldInfo.Name,
+				EnvKey:    envConfigKey,
+				SecretKey: reference.Key(),
+				Source:    secretSourceName,
+				RfValue:   reflect.Value{},
+				RfTags:    structFieldInfo.Tag,
+				Value:     "",
+			},
+			Reference: reference,
+			Path:      fieldPath,
+		})
+	}
+
+	return refsList
+}
+
+// resolveSecrets - resolution pass. Fetches all discovered secrets by one GetMany call of secret provider,
+// before assignment of values. Errors of all failed fields are aggregated in order of fields declaration...
+func (u *configVariablesPool) resolveSecrets(ctx context.Context, refsList []secrets.FieldReference) error {
+	resolvedSecretsMap, err := secrets.ResolveFieldReferences(ctx, u.secretsDataSvc, refsList)
+	if err != nil {
+		return u.e.ErrorNoWrap(err)
+	}
+
+	u.resolvedSecretsMap = resolvedSecretsMap
+
 	return nil
 }
 
+// lookupSecretReference - secret reference of secret-tagged field. Reference taken from variable value
+// with "!secret:" prefix, e.g. DB_PASSWORD="!secret:vault:billing/db#password@v3", or from secret_name tag.
+// Envconfig key used as reference by default...
+func (u *configVariablesPool) lookupSecretReference(tags reflect.StructTag, envKey string) (secrets.Reference, error) {
+	rawReference := common.LookupSecretName(tags, envKey)
+
+	value, _, isExists := u.lookupVariable(envKey)
+	if isExists && strings.HasPrefix(value, secrets.ReferencePrefix) {
+		rawReference = strings.TrimPrefix(value, secrets.ReferencePrefix)
+	}
+
+	if rawReference == "" {
+		return secrets.Reference{}, nil
+	}
+
+	reference, err := secrets.ParseReference(rawReference)
+	if err != nil {
+		return secrets.Reference{
+			Scheme:     "",
+			Path:       rawReference,
+			Field:      "",
+			Version:    "",
+			Default:    "",
+			HasDefault: false,
+		}, err //nolint:wrapcheck // it's ok, error of secrets package
+	}
+
+	return reference, nil
+}
+
+// lookupVariable - search variable value in registered variable sources and after that in process environment.
+// Sources checked in same order as they were passed to dependencies list.
+// Returns value and name of source, which provided the value...
+func (u *configVariablesPool) lookupVariable(key string) (string, string, bool) {
+	for _, sourceSvc := range u.variableSourcesList {
+		value, isExists := sourceSvc.LookupValue(key)
+		if isExists {
+			return value, sourceSvc.GetSourceName(), true
+		}
+	}
+
+	value, isExists := os.LookupEnv(key)
+
+	return value, envSourceName, isExists
+}
+
 func newConfigVarsPool(errFmtSvc errorFormatterService,
-	secretDataProviderSvc secretManagerService,
+	secretDataProviderSvc secretProviderService,
 	processedConfig interface{},
 	dependenciesSvcList []interface{},
 ) *configVariablesPool {
-	return &configVariablesPool{
-		e: errFmtSvc,
+	variableSourcesList := make([]variableSourceService, 0)
+	logger := common.NewLoggerByDependencies(dependenciesSvcList)
 
-		dependenciesSvc: dependenciesSvcList,
-		targetConfigSvc: processedConfig,
-		secretsDataSvc:  secretDataProviderSvc,
+	var envRegistrySvc environmentRegistryService = NewDefaultEnvironmentRegistry()
+
+	for _, dependencySvc := range dependenciesSvcList {
+		switch castedDependency := dependencySvc.(type) {
+		case variableSourceService:
+			logger.SourceDiscovered(castedDependency.GetSourceName(), len(variableSourcesList))
+
+			variableSourcesList = append(variableSourcesList, castedDependency)
+		case environmentRegistryService:
+			envRegistrySvc = castedDependency
+		default:
+			continue
+		}
+	}
+
+	logger.SourceDiscovered(envSourceName, len(variableSourcesList))
+
+	return &configVariablesPool{
+		e:      errFormatterOrDefault(errFmtSvc),
+		logger: logger,
+
+		dependenciesSvc:     dependenciesSvcList,
+		variableSourcesList: variableSourcesList,
+		environmentNames:    envRegistrySvc.GetNames(),
+		environmentName:     "", // will be filled on Process call
+		targetConfigSvc:     processedConfig,
+		secretsDataSvc:      secretDataProviderSvc,
 
 		envVariablesNameCount: 0,
 		envVariablesNameList:  make([]string, 0),
@@ -33,12 +33,16 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
 )
 
 func TestVarPoolBaseEnvVariables(t *testing.T) {
@@ -69,7 +73,7 @@ func TestVarPoolBaseEnvVariables(t *testing.T) {
 	baseCfg := &BaseConfig{}
 	cfgVarPool := newConfigVarsPool(MockErrorFormatterSvc, nil,
 		baseCfg, nil)
-	err := cfgVarPool.Process()
+	err := cfgVarPool.Process(context.Background())
 	if err != nil {
 		t.Errorf("%s", err)
 		return
@@ -132,9 +136,9 @@ func TestVarPoolSecretVariables(t *testing.T) {
 		TestFieldForSecretOverwrite: InitialSecretVariables["TEST_FIELD_FOR_OVERWRITE_BY_SECRET"],
 	}
 
-	cfgVarPool := newConfigVarsPool(MockErrorFormatterSvc, MockSecretService,
+	cfgVarPool := newConfigVarsPool(MockErrorFormatterSvc, secrets.AdaptSecretManager(MockSecretService),
 		testTypeStructSecrets, nil)
-	err := cfgVarPool.Process()
+	err := cfgVarPool.Process(context.Background())
 	if err != nil {
 		t.Errorf("%s", err)
 		return
@@ -223,8 +227,8 @@ func TestVarPoolVariablesWithSecretAndPrepare(t *testing.T) {
 			false),
 	}
 
-	cfgVarPool := newConfigVarsPool(MockErrorFormatterSvc, MockSecretService, testTypeStruct, nil)
-	err := cfgVarPool.Process()
+	cfgVarPool := newConfigVarsPool(MockErrorFormatterSvc, secrets.AdaptSecretManager(MockSecretService), testTypeStruct, nil)
+	err := cfgVarPool.Process(context.Background())
 	if err != nil {
 		t.Errorf("%s", err)
 		return
@@ -319,9 +323,9 @@ func TestVarPoolVariablesWithEmbeddedStructsAndSecrets(t *testing.T) {
 		},
 	}
 
-	cfgVarPool := newConfigVarsPool(MockErrorFormatterSvc, MockSecretService,
+	cfgVarPool := newConfigVarsPool(MockErrorFormatterSvc, secrets.AdaptSecretManager(MockSecretService),
 		testTypeStruct, nil)
-	err := cfgVarPool.Process()
+	err := cfgVarPool.Process(context.Background())
 	if err != nil {
 		t.Errorf("%s", err)
 		return
@@ -359,3 +363,135 @@ func TestVarPoolVariablesWithEmbeddedStructsAndSecrets(t *testing.T) {
 		t.Errorf("not equal EmbeddedFieldOne")
 	}
 }
+
+type TestEnvSpecificDefaultsConfig struct {
+	RequestTimeout time.Duration `envconfig:"ENV_DEFAULTS_TEST_REQUEST_TIMEOUT" default:"dev=5s,production=30s"`
+	RetryCount     uint8         `envconfig:"ENV_DEFAULTS_TEST_RETRY_COUNT" default:"1" default_prod:"5"`
+	LogLevel       string        `envconfig:"ENV_DEFAULTS_TEST_LOG_LEVEL" default:"debug" default_production:"info"`
+	NodeAddresses  string        `envconfig:"ENV_DEFAULTS_TEST_NODE_ADDRESSES" default:"a=b"`
+	TLSCertPath    string        `envconfig:"ENV_DEFAULTS_TEST_TLS_CERT_PATH" required_in:"production,staging"`
+}
+
+func TestVarPoolEnvironmentSpecificDefaults(t *testing.T) {
+	var MockErrorFormatterSvc = common.NewMockErrFormatter()
+
+	devCfg := &TestEnvSpecificDefaultsConfig{}
+	cfgVarPool := newConfigVarsPool(MockErrorFormatterSvc, nil, devCfg,
+		[]interface{}{&BaseConfig{Environment: EnvDev}})
+
+	err := cfgVarPool.Process(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if devCfg.RequestTimeout != 5*time.Second {
+		t.Errorf("not equal RequestTimeout for development environment")
+	}
+
+	if devCfg.RetryCount != 1 {
+		t.Errorf("not equal RetryCount for development environment")
+	}
+
+	if devCfg.LogLevel != "debug" {
+		t.Errorf("not equal LogLevel for development environment")
+	}
+
+	if devCfg.NodeAddresses != "a=b" {
+		t.Errorf("not equal NodeAddresses for development environment")
+	}
+
+	prodCfg := &TestEnvSpecificDefaultsConfig{}
+	cfgVarPool = newConfigVarsPool(MockErrorFormatterSvc, nil, prodCfg,
+		[]interface{}{&BaseConfig{Environment: EnvProduction}})
+
+	err = cfgVarPool.Process(context.Background())
+	if err == nil {
+		t.Errorf("expected error for missing required in production variable")
+		return
+	}
+
+	err = os.Setenv("ENV_DEFAULTS_TEST_TLS_CERT_PATH", "/etc/tls/cert.pem")
+	if err != nil {
+		return
+	}
+
+	defer os.Unsetenv("ENV_DEFAULTS_TEST_TLS_CERT_PATH")
+
+	prodCfg = &TestEnvSpecificDefaultsConfig{}
+	cfgVarPool = newConfigVarsPool(MockErrorFormatterSvc, nil, prodCfg,
+		[]interface{}{&BaseConfig{Environment: EnvProduction}})
+
+	err = cfgVarPool.Process(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if prodCfg.RequestTimeout != 30*time.Second {
+		t.Errorf("not equal RequestTimeout for production environment")
+	}
+
+	if prodCfg.RetryCount != 5 {
+		t.Errorf("not equal RetryCount for production environment")
+	}
+
+	if prodCfg.LogLevel != "info" {
+		t.Errorf("not equal LogLevel for production environment")
+	}
+}
+
+type TestEnvDefaultsPriorityConfig struct {
+	Timeout  time.Duration `envconfig:"ENV_DEFAULTS_TEST_TIMEOUT" default:"5s" default_prod:"10s" default_production:"30s"`
+	LogLevel string        `envconfig:"ENV_DEFAULTS_TEST_LEVEL" default:"info" default_dev:"debug"`
+}
+
+func TestVarPoolEnvironmentDefaultsPriority(t *testing.T) {
+	// canonical name tag has priority over alias tag, result must not depend on map iteration order
+	for range 20 {
+		cfg := &TestEnvDefaultsPriorityConfig{}
+
+		err := newConfigVarsPool(common.NewMockErrFormatter(), nil, cfg,
+			[]interface{}{&BaseConfig{Environment: "prod"}}).Process(context.Background())
+		if err != nil {
+			t.Errorf("%s", err)
+			return
+		}
+
+		if cfg.Timeout != 30*time.Second {
+			t.Errorf("expected default of canonical environment name: %s", cfg.Timeout)
+			return
+		}
+	}
+
+	t.Setenv(AppEnvironmentNameVariable, "")
+
+	err := os.Unsetenv(AppEnvironmentNameVariable)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	cfg := &TestEnvDefaultsPriorityConfig{}
+
+	err = newConfigVarsPool(nil, nil, cfg, nil).Process(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.LogLevel != "debug" {
+		t.Errorf("expected default of development environment without APP_ENV variable: %s", cfg.LogLevel)
+	}
+}
+
+func TestVarPoolNilTarget(t *testing.T) {
+	targetsList := []interface{}{nil, (*BaseConfig)(nil), BaseConfig{}}
+
+	for _, target := range targetsList {
+		err := NewConfigManager(nil).PrepareTo(target).Do(context.Background())
+		if !errors.Is(err, ErrPassedStructMustBeAPointer) {
+			t.Errorf("expected pointer error for target %T: %v", target, err)
+		}
+	}
+}
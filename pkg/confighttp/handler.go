@@ -0,0 +1,277 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package confighttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/config"
+	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
+)
+
+var ErrNilService = errors.New("service must not be nil")
+
+const (
+	BuildInfoPath  = "/version"
+	ConfigInfoPath = "/config"
+	MetricsPath    = "/metrics"
+
+	jsonContentType = "application/json; charset=utf-8"
+)
+
+// BuildInfoResponse - response of build info handler...
+type BuildInfoResponse struct {
+	BuildDate     time.Time `json:"build_date"`
+	ReleaseTag    string    `json:"release_tag"`
+	CommitID      string    `json:"commit_id"`
+	ShortCommitID string    `json:"short_commit_id"`
+	GoVersion     string    `json:"go_version,omitempty"`
+	VCSType       string    `json:"vcs_type,omitempty"`
+	BuildNumber   uint64    `json:"build_number"`
+	VCSModified   bool      `json:"vcs_modified"`
+}
+
+// ConfigInfoResponse - response of config info handler...
+type ConfigInfoResponse struct {
+	LastPreparedAt  *time.Time                 `json:"last_prepared_at,omitempty"`
+	ApplicationName string                     `json:"application_name,omitempty"`
+	Environment     string                     `json:"environment,omitempty"`
+	Stage           string                     `json:"stage,omitempty"`
+	Hostname        string                     `json:"hostname,omitempty"`
+	Variables       []config.EffectiveVariable `json:"variables"`
+	PID             int                        `json:"pid,omitempty"`
+}
+
+// Handler - HTTP handler of build info, config info and metrics endpoints...
+type Handler struct {
+	ldFlagSvc     ldFlagManagerService
+	baseCfgSvc    baseConfigService
+	configSvcList []effectiveConfigService
+}
+
+// ServeHTTP - route request by path suffix: /version, /config or /metrics.
+// Handler can be mounted with any path prefix...
+func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch {
+	case strings.HasSuffix(request.URL.Path, BuildInfoPath):
+		h.BuildInfoHandler().ServeHTTP(writer, request)
+	case strings.HasSuffix(request.URL.Path, ConfigInfoPath):
+		h.ConfigInfoHandler().ServeHTTP(writer, request)
+	case strings.HasSuffix(request.URL.Path, MetricsPath):
+		h.MetricsHandler().ServeHTTP(writer, request)
+	default:
+		http.NotFound(writer, request)
+	}
+}
+
+// Register - register build info, config info and metrics handlers in mux with path prefix, e.g. /debug...
+func (h *Handler) Register(mux *http.ServeMux, pathPrefix string) {
+	pathPrefix = strings.TrimSuffix(pathPrefix, "/")
+
+	mux.Handle(pathPrefix+BuildInfoPath, h.BuildInfoHandler())
+	mux.Handle(pathPrefix+ConfigInfoPath, h.ConfigInfoHandler())
+	mux.Handle(pathPrefix+MetricsPath, h.MetricsHandler())
+}
+
+// BuildInfoHandler returns handler of build info in JSON format...
+func (h *Handler) BuildInfoHandler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !isReadMethod(request) {
+			writeMethodNotAllowed(writer)
+
+			return
+		}
+
+		writeJSON(writer, h.GetBuildInfo())
+	})
+}
+
+// ConfigInfoHandler returns handler of application environment info and redacted effective config in JSON format...
+func (h *Handler) ConfigInfoHandler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !isReadMethod(request) {
+			writeMethodNotAllowed(writer)
+
+			return
+		}
+
+		writeJSON(writer, h.GetConfigInfo())
+	})
+}
+
+// GetBuildInfo returns build info of application binary...
+func (h *Handler) GetBuildInfo() *BuildInfoResponse {
+	response := &BuildInfoResponse{
+		ReleaseTag:    h.ldFlagSvc.GetReleaseTag(),
+		CommitID:      h.ldFlagSvc.GetCommitID(),
+		ShortCommitID: h.ldFlagSvc.GetShortCommitID(),
+		BuildNumber:   h.ldFlagSvc.GetBuildNumber(),
+		BuildDate:     h.ldFlagSvc.GetBuildDate().UTC(),
+		GoVersion:     "",
+		VCSType:       "",
+		VCSModified:   false,
+	}
+
+	castedBuildInfoSvc, isPossibleToCast := h.ldFlagSvc.(buildInfoProviderService)
+	if isPossibleToCast {
+		buildInfo := castedBuildInfoSvc.GetBuildInfo()
+
+		response.GoVersion = buildInfo.GoVersion
+		response.VCSType = buildInfo.VCSType
+		response.VCSModified = buildInfo.VCSModified
+	}
+
+	return response
+}
+
+// GetConfigInfo returns application environment info and redacted effective config...
+func (h *Handler) GetConfigInfo() *ConfigInfoResponse {
+	response := &ConfigInfoResponse{
+		ApplicationName: "",
+		Environment:     "",
+		Stage:           "",
+		Hostname:        "",
+		PID:             0,
+		LastPreparedAt:  nil,
+		Variables:       make([]config.EffectiveVariable, 0),
+	}
+
+	if h.baseCfgSvc != nil {
+		response.ApplicationName = h.baseCfgSvc.GetApplicationName()
+		response.Environment = h.baseCfgSvc.GetEnvironmentName()
+		response.Stage = h.baseCfgSvc.GetStageName()
+		response.Hostname = h.baseCfgSvc.GetHostName()
+		response.PID = h.baseCfgSvc.GetApplicationPID()
+	}
+
+	lastPreparedAt, isPrepared := h.getLastPreparedAt()
+	if isPrepared {
+		lastPreparedAt = lastPreparedAt.UTC()
+		response.LastPreparedAt = &lastPreparedAt
+	}
+
+	for _, configSvc := range h.configSvcList {
+		response.Variables = append(response.Variables, configSvc.GetEffectiveVariables()...)
+	}
+
+	return response
+}
+
+// getLastPreparedAt returns time of latest config preparation...
+func (h *Handler) getLastPreparedAt() (time.Time, bool) {
+	var lastPreparedAt time.Time
+
+	for _, configSvc := range h.configSvcList {
+		preparedAt := configSvc.GetLastPreparedAt()
+		if preparedAt.After(lastPreparedAt) {
+			lastPreparedAt = preparedAt
+		}
+	}
+
+	return lastPreparedAt, !lastPreparedAt.IsZero()
+}
+
+func isReadMethod(request *http.Request) bool {
+	return request.Method == http.MethodGet || request.Method == http.MethodHead
+}
+
+func writeMethodNotAllowed(writer http.ResponseWriter) {
+	writer.Header().Set("Allow", http.MethodGet+", "+http.MethodHead)
+	http.Error(writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+func writeJSON(writer http.ResponseWriter, response interface{}) {
+	rawResponse, err := json.Marshal(response)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", jsonContentType)
+	writer.WriteHeader(http.StatusOK)
+
+	_, _ = writer.Write(rawResponse)
+}
+
+// NewHandler - create handler of build info, config info and metrics endpoints.
+// baseCfgSvc can be nil interface value, configSvcList - config managers, which provide redacted effective config and
+// time of last preparation. Nil ldflag manager, nil pointer of base config and nil config managers are rejected
+// by ErrNilService error, e.g.:
+//
+//	handler, err := confighttp.NewHandler(flagManagerSvc, baseCfg, baseCfgManager, appCfgManager)
+//	if err != nil {
+//		return err
+//	}
+//
+//	handler.Register(http.DefaultServeMux, "/debug")
+func NewHandler(ldFlagSvc ldFlagManagerService,
+	baseCfgSvc baseConfigService,
+	configSvcList ...effectiveConfigService,
+) (*Handler, error) {
+	if isNilService(ldFlagSvc) {
+		return nil, errfmt.ErrorOnly(ErrNilService, "ldflag manager")
+	}
+
+	if baseCfgSvc != nil && isNilService(baseCfgSvc) {
+		return nil, errfmt.ErrorOnly(ErrNilService, "base config")
+	}
+
+	for _, configSvc := range configSvcList {
+		if isNilService(configSvc) {
+			return nil, errfmt.ErrorOnly(ErrNilService, "config manager")
+		}
+	}
+
+	return &Handler{
+		ldFlagSvc:     ldFlagSvc,
+		baseCfgSvc:    baseCfgSvc,
+		configSvcList: configSvcList,
+	}, nil
+}
+
+// isNilService - check that service is nil interface value or interface value with nil pointer...
+func isNilService(service interface{}) bool {
+	if service == nil {
+		return true
+	}
+
+	serviceValue := reflect.ValueOf(service)
+
+	return serviceValue.Kind() == reflect.Ptr && serviceValue.IsNil()
+}
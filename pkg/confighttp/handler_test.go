@@ -0,0 +1,256 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package confighttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/config"
+)
+
+type mockSecretManager struct {
+	ValuesPool map[string]string
+}
+
+func (m *mockSecretManager) GetByName(keyName string) (string, bool) {
+	result, isExists := m.ValuesPool[keyName]
+
+	return result, isExists
+}
+
+type mockBaseConfig struct{}
+
+func (c *mockBaseConfig) GetHostName() string        { return "api-7f9c" }
+func (c *mockBaseConfig) GetEnvironmentName() string { return "production" }
+func (c *mockBaseConfig) GetStageName() string       { return "blue\"green" }
+func (c *mockBaseConfig) GetApplicationPID() int     { return 42 }
+func (c *mockBaseConfig) GetApplicationName() string { return "hdwallet-api" }
+
+type testConfig struct {
+	DBHost     string `envconfig:"CONFIGHTTP_DB_HOST" default:"localhost"`
+	DBPassword string `envconfig:"CONFIGHTTP_DB_PASSWORD" secret:"true"`
+}
+
+func TestHandler(t *testing.T) {
+	defer os.Unsetenv("CONFIGHTTP_DB_HOST")
+
+	err := os.Setenv("CONFIGHTTP_DB_HOST", "db.internal")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	ldFlagSvc, err := config.NewLdFlagsManagerWithBuildInfo(common.NewMockErrFormatter(),
+		"v1.2.3", "4c3452b1a5d6e7f8091a2b3c4d5e6f708192a3b4", "4c3452b1", "42", "1728468000")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	cfgManager := config.NewConfigManager(common.NewMockErrFormatter())
+
+	err = cfgManager.PrepareTo(&testConfig{}).With(&mockSecretManager{
+		ValuesPool: map[string]string{"CONFIGHTTP_DB_PASSWORD": "super_secret"},
+	}).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	handler, err := NewHandler(ldFlagSvc, &mockBaseConfig{}, cfgManager)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	mux := http.NewServeMux()
+	handler.Register(mux, "/debug/")
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/version", nil))
+
+	buildInfo := &BuildInfoResponse{}
+
+	err = json.Unmarshal(recorder.Body.Bytes(), buildInfo)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if buildInfo.ReleaseTag != "v1.2.3" || buildInfo.ShortCommitID != "4c3452b1" || buildInfo.BuildNumber != 42 {
+		t.Errorf("not equal build info")
+	}
+
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+
+	if strings.Contains(recorder.Body.String(), "super_secret") {
+		t.Errorf("secret value must be redacted")
+	}
+
+	configInfo := &ConfigInfoResponse{}
+
+	err = json.Unmarshal(recorder.Body.Bytes(), configInfo)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if configInfo.Environment != "production" || configInfo.PID != 42 || configInfo.LastPreparedAt == nil {
+		t.Errorf("not equal config info")
+	}
+
+	variablesMap := make(map[string]config.EffectiveVariable, len(configInfo.Variables))
+	for _, variable := range configInfo.Variables {
+		variablesMap[variable.Key] = variable
+	}
+
+	if variablesMap["CONFIGHTTP_DB_HOST"].Value != "db.internal" ||
+		variablesMap["CONFIGHTTP_DB_HOST"].Source != "env" {
+		t.Errorf("not equal effective variable")
+	}
+
+	if !variablesMap["CONFIGHTTP_DB_PASSWORD"].IsRedacted ||
+		variablesMap["CONFIGHTTP_DB_PASSWORD"].Value != config.RedactedValue {
+		t.Errorf("not redacted secret variable")
+	}
+
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/metrics", nil))
+
+	metricsText := recorder.Body.String()
+	if !strings.Contains(metricsText, `app_build_info{version="v1.2.3",commit="4c3452b1a5d6e7f8091a2b3c4d5e6f708192a3b4",`) {
+		t.Errorf("not equal build info metric")
+	}
+
+	if !strings.Contains(metricsText, `stage="blue\"green"} 1`) {
+		t.Errorf("label value must be escaped")
+	}
+
+	if !strings.Contains(metricsText, "\napp_config_last_reload_timestamp ") {
+		t.Errorf("config reload metric not found")
+	}
+
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/version", nil))
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("not equal status code for POST request")
+	}
+}
+
+func TestHandlerConcurrentReload(t *testing.T) {
+	t.Setenv("CONFIGHTTP_DB_HOST", "db.internal")
+
+	secretManager := &mockSecretManager{
+		ValuesPool: map[string]string{"CONFIGHTTP_DB_PASSWORD": "super_secret"},
+	}
+
+	cfgManager := config.NewConfigManager(nil)
+	cfgManager.PrepareTo(&testConfig{}).With(secretManager)
+
+	handler, err := NewHandler(config.NewLdFlagsManagerFixture(config.LdFlagsFixture{}), &mockBaseConfig{}, cfgManager)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	mux := http.NewServeMux()
+	handler.Register(mux, "/debug/")
+
+	reloadDone := make(chan error, 1)
+
+	go func() {
+		for range 50 {
+			err := cfgManager.Do(context.Background())
+			if err != nil {
+				reloadDone <- err
+
+				return
+			}
+		}
+
+		reloadDone <- nil
+	}()
+
+	for range 50 {
+		for _, path := range []string{"/debug/config", "/debug/metrics"} {
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if recorder.Code != http.StatusOK {
+				t.Errorf("not equal status code of %s: %d", path, recorder.Code)
+			}
+		}
+	}
+
+	err = <-reloadDone
+	if err != nil {
+		t.Errorf("%s", err)
+	}
+}
+
+func TestHandlerNilServices(t *testing.T) {
+	ldFlagSvc := config.NewLdFlagsManagerFixture(config.LdFlagsFixture{})
+
+	_, err := NewHandler(ldFlagSvc, (*mockBaseConfig)(nil))
+	if !errors.Is(err, ErrNilService) {
+		t.Errorf("expected error of nil pointer of base config: %v", err)
+	}
+
+	_, err = NewHandler(nil, &mockBaseConfig{})
+	if !errors.Is(err, ErrNilService) {
+		t.Errorf("expected error of nil ldflag manager: %v", err)
+	}
+
+	handler, err := NewHandler(ldFlagSvc, nil)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ConfigInfoHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ConfigInfoPath, nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("not equal status code: %d", recorder.Code)
+	}
+}
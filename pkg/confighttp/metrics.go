@@ -0,0 +1,124 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package confighttp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	buildInfoMetricName        = "app_build_info"
+	configLastReloadMetricName = "app_config_last_reload_timestamp"
+)
+
+// MetricsHandler returns handler of build info and config reload metrics in Prometheus text exposition format...
+func (h *Handler) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !isReadMethod(request) {
+			writeMethodNotAllowed(writer)
+
+			return
+		}
+
+		writer.Header().Set("Content-Type", metricsContentType)
+		writer.WriteHeader(http.StatusOK)
+
+		_, _ = writer.Write([]byte(h.RenderMetrics()))
+	})
+}
+
+// RenderMetrics returns build info and config reload metrics in Prometheus text exposition format...
+func (h *Handler) RenderMetrics() string {
+	builder := &strings.Builder{}
+
+	labelsList := [][2]string{
+		{"version", h.ldFlagSvc.GetReleaseTag()},
+		{"commit", h.ldFlagSvc.GetCommitID()},
+		{"short_commit", h.ldFlagSvc.GetShortCommitID()},
+		{"build_number", strconv.FormatUint(h.ldFlagSvc.GetBuildNumber(), 10)},
+	}
+
+	if h.baseCfgSvc != nil {
+		labelsList = append(labelsList,
+			[2]string{"application", h.baseCfgSvc.GetApplicationName()},
+			[2]string{"environment", h.baseCfgSvc.GetEnvironmentName()},
+			[2]string{"stage", h.baseCfgSvc.GetStageName()},
+		)
+	}
+
+	writeMetricHeader(builder, buildInfoMetricName, "Build information of application binary.")
+	builder.WriteString(buildInfoMetricName)
+	writeMetricLabels(builder, labelsList)
+	builder.WriteString(" 1\n")
+
+	lastPreparedAt, isPrepared := h.getLastPreparedAt()
+	if !isPrepared {
+		return builder.String()
+	}
+
+	writeMetricHeader(builder, configLastReloadMetricName,
+		"Unix timestamp of last successful config preparation.")
+	builder.WriteString(configLastReloadMetricName + " ")
+	builder.WriteString(strconv.FormatFloat(float64(lastPreparedAt.UnixMilli())/1000, 'f', -1, 64))
+	builder.WriteString("\n")
+
+	return builder.String()
+}
+
+func writeMetricHeader(builder *strings.Builder, name, help string) {
+	builder.WriteString("# HELP " + name + " " + help + "\n")
+	builder.WriteString("# TYPE " + name + " gauge\n")
+}
+
+func writeMetricLabels(builder *strings.Builder, labelsList [][2]string) {
+	builder.WriteString("{")
+
+	for i, label := range labelsList {
+		if i != 0 {
+			builder.WriteString(",")
+		}
+
+		builder.WriteString(label[0] + "=\"" + escapeLabelValue(label[1]) + "\"")
+	}
+
+	builder.WriteString("}")
+}
+
+// escapeLabelValue - escape backslash, double-quote and line feed characters of label value...
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -35,13 +35,20 @@ package errors
 import "sync"
 
 //nolint:gochecknoglobals // it's ok
-var errorsFmtService errorFormatterService = nil
+var (
+	errorsFmtService errorFormatterService = NewStdFormatter()
+	errorsFmtOnce    sync.Once
+)
 
+// InitInternalFmt - replace default standard library formatter of package-level functions by passed formatter.
+// Only first call with non-nil formatter takes effect...
 func InitInternalFmt(fmtSvc errorFormatterService) {
-	sync.OnceFunc(func() {
-		if errorsFmtService == nil {
-			errorsFmtService = fmtSvc
-		}
+	if fmtSvc == nil {
+		return
+	}
+
+	errorsFmtOnce.Do(func() {
+		errorsFmtService = fmtSvc
 	})
 }
 
@@ -0,0 +1,177 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// DefaultErrorCode - code of error without code...
+const DefaultErrorCode = -1
+
+var _ errorFormatterService = (*stdFormatter)(nil)
+
+// codeError - error with code. Supports errors.Is and errors.As by Unwrap function...
+type codeError struct {
+	err  error
+	code int
+}
+
+func (e *codeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codeError) Unwrap() error {
+	return e.err
+}
+
+// stdFormatter - error formatter based on standard library errors and fmt packages.
+// Used by default if lib-errors service is not injected...
+type stdFormatter struct{}
+
+func (f *stdFormatter) ErrorWithCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+
+	return &codeError{
+		err:  err,
+		code: code,
+	}
+}
+
+func (f *stdFormatter) ErrWithCode(err error, code int) error {
+	return f.ErrorWithCode(err, code)
+}
+
+func (f *stdFormatter) ErrorGetCode(err error) int {
+	var errWithCode *codeError
+	if errors.As(err, &errWithCode) {
+		return errWithCode.code
+	}
+
+	return DefaultErrorCode
+}
+
+func (f *stdFormatter) ErrGetCode(err error) int {
+	return f.ErrorGetCode(err)
+}
+
+func (f *stdFormatter) ErrorNoWrap(err error) error {
+	return err
+}
+
+func (f *stdFormatter) ErrNoWrap(err error) error {
+	return err
+}
+
+// ErrorOnly - add details to error message, e.g. "error message: detail1, detail2"...
+func (f *stdFormatter) ErrorOnly(err error, details ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	if len(details) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", err, strings.Join(details, ", "))
+}
+
+// Error - add caller function name and details to error message,
+// e.g. "package.Function -> error message: detail1, detail2"...
+func (f *stdFormatter) Error(err error, details ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%s -> %w", callerName(), f.ErrorOnly(err, details...))
+}
+
+func (f *stdFormatter) Errorf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%s -> %w: %s", callerName(), err, fmt.Sprintf(format, args...))
+}
+
+func (f *stdFormatter) NewError(details ...string) error {
+	//nolint:err113 // it's ok, dynamic error is a purpose of function
+	return errors.New(strings.Join(details, ", "))
+}
+
+func (f *stdFormatter) NewErrorf(format string, args ...interface{}) error {
+	//nolint:err113 // it's ok, dynamic error is a purpose of function
+	return fmt.Errorf(format, args...)
+}
+
+// callerName returns name of first function outside errors package in call stack...
+func callerName() string {
+	const maxCallersDepth = 8
+
+	programCounters := make([]uintptr, maxCallersDepth)
+	// skip runtime.Callers and callerName functions
+	count := runtime.Callers(2, programCounters)
+	frames := runtime.CallersFrames(programCounters[:count])
+
+	packagePrefix := reflect.TypeOf(stdFormatter{}).PkgPath() + "."
+
+	for {
+		frame, hasMore := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			functionName := frame.Function
+
+			lastSlashIndex := strings.LastIndex(functionName, "/")
+			if lastSlashIndex != -1 {
+				functionName = functionName[lastSlashIndex+1:]
+			}
+
+			return functionName
+		}
+
+		if !hasMore {
+			return "unknown"
+		}
+	}
+}
+
+// NewStdFormatter - create error formatter based on standard library. Formatter supports error codes,
+// wrapping of errors by details and errors.Is / errors.As functions...
+func NewStdFormatter() *stdFormatter {
+	return &stdFormatter{}
+}
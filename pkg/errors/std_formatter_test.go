@@ -0,0 +1,97 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package errors
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errTestBase = errors.New("test base error")
+
+type mockFormatter struct {
+	stdFormatter
+}
+
+func (f *mockFormatter) ErrorOnly(_ error, _ ...string) error {
+	return errTestBase
+}
+
+func TestStdFormatter(t *testing.T) {
+	formatter := NewStdFormatter()
+
+	err := formatter.ErrorWithCode(formatter.ErrorOnly(errTestBase, "detail_one", "detail_two"), 404)
+	if !errors.Is(err, errTestBase) {
+		t.Errorf("error with code and details must wrap base error")
+	}
+
+	if err.Error() != "test base error: detail_one, detail_two" {
+		t.Errorf("not equal error message: %s", err)
+	}
+
+	if formatter.ErrorGetCode(formatter.ErrorNoWrap(err)) != 404 {
+		t.Errorf("not equal error code")
+	}
+
+	if formatter.ErrGetCode(errTestBase) != DefaultErrorCode {
+		t.Errorf("error without code must have default code")
+	}
+
+	err = formatter.Errorf(err, "value %d", 42)
+	if !errors.Is(err, errTestBase) || formatter.ErrorGetCode(err) != 404 {
+		t.Errorf("formatted error must wrap base error with code")
+	}
+
+	if !strings.HasSuffix(err.Error(), "-> test base error: detail_one, detail_two: value 42") {
+		t.Errorf("not equal formatted error message: %s", err)
+	}
+
+	if formatter.ErrorOnly(nil) != nil || formatter.ErrorWithCode(nil, 1) != nil || formatter.Error(nil) != nil {
+		t.Errorf("nil error must stay nil")
+	}
+}
+
+func TestInitInternalFmt(t *testing.T) {
+	if !errors.Is(ErrorOnly(errTestBase, "detail"), errTestBase) {
+		t.Errorf("default formatter must be used by package-level functions")
+	}
+
+	InitInternalFmt(nil)
+	InitInternalFmt(&mockFormatter{})
+
+	err := ErrorOnly(errors.New("another error"))
+	if err != errTestBase { //nolint:errorlint // formatter must return exactly mock error
+		t.Errorf("formatter must be replaced by InitInternalFmt")
+	}
+}
@@ -32,6 +32,14 @@
 
 package jsonconfig
 
+import (
+	"context"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/config"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
+)
+
 type configService interface {
 	Prepare() error
 	PrepareWith(cfgSrv ...interface{}) error
@@ -41,6 +49,11 @@ type secretManagerService interface {
 	GetByName(keyName string) (string, bool)
 }
 
+type secretProviderService interface {
+	Get(ctx context.Context, key string) (secrets.Secret, error)
+	GetMany(ctx context.Context, keysList []string) (map[string]secrets.Secret, error)
+}
+
 //nolint:interfacebloat //it's ok here, we need it we must use it as one big interface
 type errorFormatterService interface {
 	ErrorWithCode(err error, code int) error
@@ -57,3 +70,24 @@ type errorFormatterService interface {
 	NewError(details ...string) error
 	NewErrorf(format string, args ...interface{}) error
 }
+
+type environmentNameProviderService interface {
+	GetEnvironmentName() string
+}
+
+type environmentProviderService interface {
+	GetEnvironment() *config.EnvironmentDefinition
+}
+
+type environmentNamesProviderService interface {
+	GetNames() map[string]string
+}
+
+type variableSourceService interface {
+	LookupValue(key string) (string, bool)
+	GetSourceName() string
+}
+
+type releaseVersionProviderService interface {
+	GetReleaseVersion() (*common.SemVer, error)
+}
@@ -0,0 +1,452 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+var (
+	ErrIncludeCycle            = errors.New("include cycle")
+	ErrIncludeOutsideRoot      = errors.New("included file is outside of allowed root directory")
+	ErrIncludeRootIsNotDefined = errors.New("root directory of included files is not defined")
+	ErrWrongIncludeRoot        = errors.New("root directory of included files must be a valid path of fs.FS")
+	ErrWrongInclude            = errors.New("wrong include")
+)
+
+const (
+	// MetaKeyInclude - top-level key with path or list of paths of included files. Included files are merged
+	// in order of list, document with $include key is merged over them...
+	MetaKeyInclude = "$include"
+	// MetaKeyRef - key of object, which will be replaced by content of referenced file. Other keys of
+	// object are merged over referenced content...
+	MetaKeyRef = "$ref"
+)
+
+// documentComposer - composer of JSON document from multiple files by $include and $ref keys.
+// Paths of included files are relative to including file. All files must be inside root directory.
+// Documents are deep-merged:
+//   - objects are merged key by key recursively
+//   - arrays, strings, numbers, booleans and nulls of overriding document replace values of base document
+type documentComposer struct {
+	logger *common.Logger
+	// files - reader of included files, files of fs.FS are included from same fs.FS...
+	files *sourceFilesReader
+	// rootDir - absolute path of allowed root directory with resolved symlinks...
+	rootDir string
+	// filesStack - chain of files in processing, used for detection of include cycles...
+	filesStack []string
+	// loadedCount - count of loaded included and referenced files...
+	loadedCount int
+	isJSONC     bool
+}
+
+// isComposable - check that document contains $include or $ref keys...
+func isComposable(data []byte) bool {
+	return bytes.Contains(data, []byte(`"`+MetaKeyInclude+`"`)) || bytes.Contains(data, []byte(`"`+MetaKeyRef+`"`))
+}
+
+// Compose - resolve $include and $ref keys of document, returns merged document and origins of its values.
+// Returns nil, if document doesn't contain $include and $ref keys...
+func (c *documentComposer) Compose(data []byte, filePath string) ([]byte, documentOrigins, error) {
+	switch {
+	case filePath == "":
+	case c.files.fileSystem != nil:
+		c.filesStack = append(c.filesStack, path.Clean(filePath))
+	default:
+		realPath, err := filepath.EvalSymlinks(filePath)
+		if err != nil {
+			return nil, nil, err //nolint:wrapcheck // it's ok, filepath error
+		}
+
+		absPath, err := filepath.Abs(realPath)
+		if err != nil {
+			return nil, nil, err //nolint:wrapcheck // it's ok, filepath error
+		}
+
+		c.filesStack = append(c.filesStack, absPath)
+	}
+
+	document, origins, err := c.parse(data, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	composedDocument, origins, err := c.composeDocument(document, origins, c.currentDir())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.loadedCount == 0 {
+		return nil, nil, nil
+	}
+
+	composedData, err := json.Marshal(composedDocument)
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck // it's ok, json error
+	}
+
+	return composedData, origins, nil
+}
+
+// composeDocument - resolve top-level $include key and $ref keys of document...
+func (c *documentComposer) composeDocument(document interface{},
+	origins documentOrigins,
+	baseDir string,
+) (interface{}, documentOrigins, error) {
+	document, err := c.resolveRefs(document, "", origins, baseDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	object, isObject := document.(map[string]interface{})
+	if !isObject {
+		return document, origins, nil
+	}
+
+	rawInclude, hasInclude := object[MetaKeyInclude]
+	if !hasInclude {
+		return document, origins, nil
+	}
+
+	delete(object, MetaKeyInclude)
+	origins.Remove("/" + escapeJSONPointerToken(MetaKeyInclude))
+
+	includesList, err := parseIncludesList(rawInclude)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result interface{} = map[string]interface{}{}
+
+	resultOrigins := make(documentOrigins)
+
+	for _, includePath := range includesList {
+		includedDocument, includedOrigins, loadErr := c.loadFile(includePath, baseDir)
+		if loadErr != nil {
+			return nil, nil, loadErr
+		}
+
+		result = mergeDocuments(result, includedDocument)
+		resultOrigins.Merge(includedOrigins)
+	}
+
+	resultOrigins.Merge(origins)
+
+	return mergeDocuments(result, object), resultOrigins, nil
+}
+
+// resolveRefs - replace objects with $ref key by content of referenced files. Origins of referenced values
+// are placed in origins of document by pointer of replaced object...
+func (c *documentComposer) resolveRefs(value interface{},
+	pointer string,
+	origins documentOrigins,
+	baseDir string,
+) (interface{}, error) {
+	switch castedValue := value.(type) {
+	case map[string]interface{}:
+		for key, item := range castedValue {
+			resolvedItem, err := c.resolveRefs(item, pointer+"/"+escapeJSONPointerToken(key), origins, baseDir)
+			if err != nil {
+				return nil, err
+			}
+
+			castedValue[key] = resolvedItem
+		}
+
+		rawRef, hasRef := castedValue[MetaKeyRef]
+		if !hasRef {
+			return castedValue, nil
+		}
+
+		refPath, isString := rawRef.(string)
+		if !isString || refPath == "" {
+			return nil, fmt.Errorf("%w: %s value must be a non-empty string", ErrWrongInclude, MetaKeyRef)
+		}
+
+		delete(castedValue, MetaKeyRef)
+		origins.Remove(pointer + "/" + escapeJSONPointerToken(MetaKeyRef))
+
+		referencedDocument, referencedOrigins, err := c.loadFile(refPath, baseDir)
+		if err != nil {
+			return nil, err
+		}
+
+		_, isObject := referencedDocument.(map[string]interface{})
+		if !isObject && len(castedValue) != 0 {
+			return nil, fmt.Errorf("%w: %s: keys of object can't be merged over non-object document",
+				ErrWrongInclude, refPath)
+		}
+
+		overrideOrigins := origins.Subtree(pointer)
+		origins.Remove(pointer)
+
+		if len(castedValue) == 0 {
+			origins.Merge(referencedOrigins.Rebase(pointer))
+
+			return referencedDocument, nil
+		}
+
+		referencedOrigins.Merge(overrideOrigins)
+		origins.Merge(referencedOrigins.Rebase(pointer))
+
+		return mergeDocuments(referencedDocument, castedValue), nil
+	case []interface{}:
+		for i, item := range castedValue {
+			resolvedItem, err := c.resolveRefs(item, pointer+"/"+strconv.Itoa(i), origins, baseDir)
+			if err != nil {
+				return nil, err
+			}
+
+			castedValue[i] = resolvedItem
+		}
+
+		return castedValue, nil
+	default:
+		return value, nil
+	}
+}
+
+// loadFile - read, parse and compose included file. Returns composed document and origins of its values...
+func (c *documentComposer) loadFile(includePath, baseDir string) (interface{}, documentOrigins, error) {
+	if c.rootDir == "" {
+		return nil, nil, ErrIncludeRootIsNotDefined
+	}
+
+	c.loadedCount++
+
+	filePath, err := c.resolvePath(includePath, baseDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, processedPath := range c.filesStack {
+		if processedPath == filePath {
+			return nil, nil, fmt.Errorf("%w: %s -> %s",
+				ErrIncludeCycle, strings.Join(c.filesStack, " -> "), filePath)
+		}
+	}
+
+	c.logger.SourceDiscovered(fileSourceName+":"+filePath, len(c.filesStack))
+
+	data, err := c.files.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.filesStack = append(c.filesStack, filePath)
+	defer func() { c.filesStack = c.filesStack[:len(c.filesStack)-1] }()
+
+	document, origins, err := c.parse(data, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.composeDocument(document, origins, c.files.Dir(filePath))
+}
+
+// resolvePath returns absolute path of included file. Path must be inside root directory after symlinks resolving...
+func (c *documentComposer) resolvePath(includePath, baseDir string) (string, error) {
+	if c.files.fileSystem != nil {
+		// paths of fs.FS can't point outside of root of fs.FS
+		filePath := path.Join(baseDir, includePath)
+		if path.IsAbs(includePath) || !fs.ValidPath(filePath) {
+			return "", fmt.Errorf("%w: %s", ErrIncludeOutsideRoot, includePath)
+		}
+
+		if c.rootDir != "." && filePath != c.rootDir && !strings.HasPrefix(filePath, c.rootDir+"/") {
+			return "", fmt.Errorf("%w: %s", ErrIncludeOutsideRoot, includePath)
+		}
+
+		return filePath, nil
+	}
+
+	filePath := includePath
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(baseDir, filePath)
+	}
+
+	realPath, err := filepath.EvalSymlinks(filePath)
+	if err != nil {
+		return "", err //nolint:wrapcheck // it's ok, file path error
+	}
+
+	relPath, err := filepath.Rel(c.rootDir, realPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s", ErrIncludeOutsideRoot, includePath)
+	}
+
+	return realPath, nil
+}
+
+// parse - parse JSON or JSONC document. Syntax errors are reported with position in file.
+// Returns parsed document and origins of its values...
+func (c *documentComposer) parse(data []byte, filePath string) (interface{}, documentOrigins, error) {
+	sourceData := data
+
+	if c.isJSONC || isJSONCFile(filePath) {
+		strippedData, err := stripJSONC(data)
+		if err != nil {
+			return nil, nil, withFilePath(err, filePath)
+		}
+
+		data = strippedData
+	}
+
+	validatorSvc := newDocumentValidator(data, sourceData, false)
+	validatorSvc.offsetsMap = make(map[string]int)
+
+	_, err := validatorSvc.Validate(nil)
+	if err != nil {
+		return nil, nil, withFilePath(err, filePath)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+
+	err = decoder.Decode(&document)
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck // it's ok, json error
+	}
+
+	return document, newDocumentOrigins(filePath, sourceData, validatorSvc.offsetsMap), nil
+}
+
+func (c *documentComposer) currentDir() string {
+	if len(c.filesStack) == 0 {
+		return c.rootDir
+	}
+
+	return c.files.Dir(c.filesStack[len(c.filesStack)-1])
+}
+
+func parseIncludesList(rawInclude interface{}) ([]string, error) {
+	switch castedInclude := rawInclude.(type) {
+	case string:
+		return []string{castedInclude}, nil
+	case []interface{}:
+		includesList := make([]string, 0, len(castedInclude))
+
+		for _, item := range castedInclude {
+			includePath, isString := item.(string)
+			if !isString || includePath == "" {
+				return nil, fmt.Errorf("%w: %s items must be non-empty strings", ErrWrongInclude, MetaKeyInclude)
+			}
+
+			includesList = append(includesList, includePath)
+		}
+
+		return includesList, nil
+	default:
+		return nil, fmt.Errorf("%w: %s must be a string or array of strings", ErrWrongInclude, MetaKeyInclude)
+	}
+}
+
+// mergeDocuments - deep merge of override document over base document. Objects are merged key by key,
+// other values of override document replace values of base document...
+func mergeDocuments(base, override interface{}) interface{} {
+	baseObject, isBaseObject := base.(map[string]interface{})
+	overrideObject, isOverrideObject := override.(map[string]interface{})
+
+	if !isBaseObject || !isOverrideObject {
+		return override
+	}
+
+	for key, overrideValue := range overrideObject {
+		baseValue, isExists := baseObject[key]
+		if isExists {
+			baseObject[key] = mergeDocuments(baseValue, overrideValue)
+
+			continue
+		}
+
+		baseObject[key] = overrideValue
+	}
+
+	return baseObject
+}
+
+// newDocumentComposer - create composer of JSON document. For files of fs.FS root directory is a slash-separated
+// path of fs.FS, root of fs.FS used as root directory if empty root directory passed...
+func newDocumentComposer(logger *common.Logger,
+	files *sourceFilesReader,
+	rootDir string,
+	isJSONC bool,
+) (*documentComposer, error) {
+	composer := &documentComposer{
+		logger:      logger,
+		files:       files,
+		rootDir:     "",
+		filesStack:  make([]string, 0),
+		loadedCount: 0,
+		isJSONC:     isJSONC,
+	}
+
+	if files.fileSystem != nil {
+		composer.rootDir = path.Clean(rootDir) // "." for empty root directory
+
+		if !fs.ValidPath(composer.rootDir) {
+			return nil, ErrWrongIncludeRoot
+		}
+
+		return composer, nil
+	}
+
+	if rootDir == "" {
+		return composer, nil
+	}
+
+	absRootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // it's ok, filepath error
+	}
+
+	composer.rootDir, err = filepath.EvalSymlinks(absRootDir)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // it's ok, filepath error
+	}
+
+	return composer, nil
+}
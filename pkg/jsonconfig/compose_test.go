@@ -0,0 +1,195 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, dirPath string, filesMap map[string]string) {
+	t.Helper()
+
+	for filePath, content := range filesMap {
+		fullPath := filepath.Join(dirPath, filePath)
+
+		err := os.MkdirAll(filepath.Dir(fullPath), 0o700)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+
+		err = os.WriteFile(fullPath, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+	}
+}
+
+func TestServiceIncludeAndRef(t *testing.T) {
+	rootDir := t.TempDir()
+
+	writeTestFiles(t, rootDir, map[string]string{
+		"config.json": `{
+			"$include": ["common.json"],
+			"top_level_field_int": 7,
+			"list": [{"$ref": "nodes/btc.json", "int_field_one": 9}]
+		}`,
+		"common.json":     `{"top_level_field_int": 1, "list": [{"int_field_one": 100}, {"int_field_one": 200}]}`,
+		"nodes/btc.json":  `{"string_field": "btc", "int_field_one": 1, "db_port": {"$ref": "port.json"}}`,
+		"nodes/port.json": `"8332"`,
+	})
+
+	cfg := &MixedJSONCase{}
+
+	err := NewService(nil).PrepareTo(cfg).PrepareFromFile(filepath.Join(rootDir, "config.json")).
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.TopLevelField != 7 || len(cfg.List) != 1 {
+		t.Errorf("not equal merged document: %+v", cfg)
+		return
+	}
+
+	if cfg.List[0].StringField != "btc" || cfg.List[0].IntFieldOne != 9 || cfg.List[0].GetPort() != 8332 {
+		t.Errorf("not equal referenced document: %+v", cfg.List[0])
+	}
+
+	writeTestFiles(t, rootDir, map[string]string{
+		"config.json": `{
+			"$include": ["common.json"],
+			"top_level_field_int": 7,
+			"list": [{"$ref": "nodes/btc.json", "int_field_one": "9"}]
+		}`,
+		"nodes/btc.json": `{"db_port": "8332",
+			"string_field": 1}`,
+	})
+
+	err = NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFromFile(filepath.Join(rootDir, "config.json")).
+		Do(context.Background())
+
+	expectedErrorsList := []struct {
+		file    string
+		pointer string
+		line    int
+		column  int
+	}{
+		{"config.json", "/list/0/int_field_one", 4, 57},
+		{"nodes/btc.json", "/list/0/string_field", 2, 20},
+	}
+
+	for _, expectedErr := range expectedErrorsList {
+		if !hasDecodeErrorAt(err, expectedErr.pointer, expectedErr.file, expectedErr.line, expectedErr.column) {
+			t.Errorf("expected decode error of %s in %s:%d:%d: %v",
+				expectedErr.pointer, expectedErr.file, expectedErr.line, expectedErr.column, err)
+		}
+	}
+}
+
+// hasDecodeErrorAt - check that joined errors contain decode error by pointer in file with path suffix and position...
+func hasDecodeErrorAt(err error, pointer, fileSuffix string, line, column int) bool {
+	joinedErr, isJoined := err.(interface{ Unwrap() []error }) //nolint:errorlint // it's ok, list of joined errors
+	if !isJoined {
+		return false
+	}
+
+	for _, itemErr := range joinedErr.Unwrap() {
+		var decodeErr *DecodeError
+		if !errors.As(itemErr, &decodeErr) || decodeErr.Pointer != pointer {
+			continue
+		}
+
+		return strings.HasSuffix(decodeErr.File, fileSuffix) && decodeErr.Line == line && decodeErr.Column == column
+	}
+
+	return false
+}
+
+func TestServiceIncludeErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	rootDir := filepath.Join(tempDir, "config")
+
+	writeTestFiles(t, tempDir, map[string]string{
+		"config/a.json":       `{"$include": "b.json"}`,
+		"config/b.json":       `{"$include": ["./nested/../a.json"]}`,
+		"config/outside.json": `{"$include": "../secret.json"}`,
+		"config/wrong.json":   `{"$include": 1}`,
+		"config/syntax.json":  `{"$include": "broken.json"}`,
+		"config/broken.json":  "{\n  \"key\": [1, 2\n}",
+		"secret.json":         `{}`,
+	})
+
+	casesList := []struct {
+		fileName    string
+		expectedErr error
+	}{
+		{"a.json", ErrIncludeCycle},
+		{"outside.json", ErrIncludeOutsideRoot},
+		{"wrong.json", ErrWrongInclude},
+		{"syntax.json", ErrInvalidJSON},
+	}
+
+	for _, testCase := range casesList {
+		err := NewService(nil).PrepareTo(&MixedJSONCase{}).
+			PrepareFromFile(filepath.Join(rootDir, testCase.fileName)).Do(context.Background())
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("%s: expected error %s, got %v", testCase.fileName, testCase.expectedErr, err)
+		}
+	}
+
+	var decodeErr *DecodeError
+
+	err := NewService(nil).PrepareTo(&MixedJSONCase{}).
+		PrepareFromFile(filepath.Join(rootDir, "syntax.json")).Do(context.Background())
+	if !errors.As(err, &decodeErr) || filepath.Base(decodeErr.File) != "broken.json" || decodeErr.Line != 3 {
+		t.Errorf("expected syntax error with position in included file: %v", err)
+	}
+
+	err = NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFrom([]byte(`{"$include": "a.json"}`)).
+		Do(context.Background())
+	if !errors.Is(err, ErrIncludeRootIsNotDefined) {
+		t.Errorf("expected error of not defined root: %v", err)
+	}
+
+	err = NewService(nil).WithIncludeRoot(tempDir).PrepareTo(&MixedJSONCase{}).
+		PrepareFromFile(filepath.Join(rootDir, "outside.json")).Do(context.Background())
+	if err != nil {
+		t.Errorf("included file must be allowed by include root: %s", err)
+	}
+}
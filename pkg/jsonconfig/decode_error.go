@@ -0,0 +1,145 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mailru/easyjson/jlexer"
+)
+
+var (
+	ErrInvalidJSON     = errors.New("invalid JSON")
+	ErrUnexpectedType  = errors.New("unexpected type of value")
+	ErrValueOutOfRange = errors.New("value out of range")
+	ErrUnknownKey      = errors.New("unknown key")
+)
+
+// DecodeError - error of JSON config decoding with position of error in source document.
+// Line and column are 1-based, column counted in characters...
+type DecodeError struct {
+	Cause error
+	// File - path of source file, empty for config passed by PrepareFrom function.
+	// For composed documents - path of included or referenced file with offending element...
+	File string
+	// Pointer - JSON pointer (RFC 6901) to offending element, empty for document root...
+	Pointer string
+	// Expected - expected type of value: object, array, string, boolean, integer or number.
+	// Empty for syntax errors and unknown keys...
+	Expected string
+	Line     int
+	Column   int
+	Offset   int
+}
+
+func (e *DecodeError) Error() string {
+	position := strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Column)
+	if e.Line == 0 {
+		// position is unknown, e.g. for documents composed from multiple files
+		position = ""
+	}
+
+	if e.File != "" {
+		position = strings.TrimSuffix(e.File+":"+position, ":")
+	}
+
+	if e.Pointer != "" {
+		position = strings.TrimPrefix(position+": "+e.Pointer, ": ")
+	}
+
+	if position == "" {
+		return e.Cause.Error()
+	}
+
+	return position + ": " + e.Cause.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Cause
+}
+
+// newDecodeError - create decode error with line and column of offset in source document...
+func newDecodeError(cause error, data []byte, offset int) *DecodeError {
+	offset = min(max(offset, 0), len(data))
+
+	line := bytes.Count(data[:offset], []byte{'\n'}) + 1
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+
+	return &DecodeError{
+		Cause:    cause,
+		File:     "",
+		Pointer:  "",
+		Expected: "",
+		Line:     line,
+		Column:   utf8.RuneCount(data[lineStart:offset]) + 1,
+		Offset:   offset,
+	}
+}
+
+// withFilePath - set file path of decode error...
+func withFilePath(err error, filePath string) error {
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		decodeErr.File = filePath
+	}
+
+	return err
+}
+
+// withoutPosition - clear line, column and offset of decode error, e.g. for error in transformed document
+// with unknown origin of offending element...
+func withoutPosition(err error) error {
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		decodeErr.Line, decodeErr.Column, decodeErr.Offset = 0, 0, 0
+	}
+
+	return err
+}
+
+// newLexerDecodeError - convert jlexer error to decode error with position in source document...
+func newLexerDecodeError(err error, data []byte, filePath string) error {
+	var lexerErr *jlexer.LexerError
+	if !errors.As(err, &lexerErr) {
+		return err
+	}
+
+	decodeErr := newDecodeError(errors.New(lexerErr.Reason), data, lexerErr.Offset) //nolint:err113 // it's ok
+	decodeErr.File = filePath
+
+	return decodeErr
+}
@@ -0,0 +1,213 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// nodeOrigin - origin of value of composed document - source file and offset of value in source file...
+type nodeOrigin struct {
+	file string
+	// sourceData - source document of file, used for calculation of line and column...
+	sourceData []byte
+	offset     int
+}
+
+// isObject - check that value of origin is a JSON object...
+func (o nodeOrigin) isObject() bool {
+	return o.offset < len(o.sourceData) && o.sourceData[o.offset] == '{'
+}
+
+// documentOrigins - origins of values of composed document by JSON pointers. Used for mapping of decode errors
+// of composed document to files and positions of included values...
+type documentOrigins map[string]nodeOrigin
+
+// newDocumentOrigins - origins of all values of source file by offsets of values...
+func newDocumentOrigins(filePath string, sourceData []byte, offsetsMap map[string]int) documentOrigins {
+	origins := make(documentOrigins, len(offsetsMap))
+
+	for pointer, offset := range offsetsMap {
+		origins[pointer] = nodeOrigin{
+			file:       filePath,
+			sourceData: sourceData,
+			offset:     offset,
+		}
+	}
+
+	return origins
+}
+
+// Rebase returns origins with pointers relative to passed pointer, e.g. origins of referenced file
+// placed in document by $ref key...
+func (o documentOrigins) Rebase(pointer string) documentOrigins {
+	result := make(documentOrigins, len(o))
+
+	for nodePointer, origin := range o {
+		result[pointer+nodePointer] = origin
+	}
+
+	return result
+}
+
+// Subtree returns origins of value by pointer and its nested values with pointers relative to passed pointer...
+func (o documentOrigins) Subtree(pointer string) documentOrigins {
+	result := make(documentOrigins)
+
+	for nodePointer, origin := range o {
+		if isPointerInside(nodePointer, pointer) {
+			result[nodePointer[len(pointer):]] = origin
+		}
+	}
+
+	return result
+}
+
+// Remove - remove origins of value by pointer and its nested values...
+func (o documentOrigins) Remove(pointer string) {
+	for nodePointer := range o {
+		if isPointerInside(nodePointer, pointer) {
+			delete(o, nodePointer)
+		}
+	}
+}
+
+// ShiftItems - shift indexes of origins of array items, starting from passed index, by delta,
+// e.g. after insertion or removal of array item...
+func (o documentOrigins) ShiftItems(arrayPointer string, fromIndex, delta int) {
+	prefix := arrayPointer + "/"
+	shiftedOrigins := make(documentOrigins)
+
+	for nodePointer, origin := range o {
+		if !strings.HasPrefix(nodePointer, prefix) {
+			continue
+		}
+
+		element, nestedPointer, hasNested := strings.Cut(nodePointer[len(prefix):], "/")
+
+		index, err := strconv.Atoi(element)
+		if err != nil || index < fromIndex {
+			continue
+		}
+
+		if hasNested {
+			nestedPointer = "/" + nestedPointer
+		}
+
+		delete(o, nodePointer)
+		shiftedOrigins[prefix+strconv.Itoa(index+delta)+nestedPointer] = origin
+	}
+
+	for nodePointer, origin := range shiftedOrigins {
+		o[nodePointer] = origin
+	}
+}
+
+// Merge - merge origins of override document by rules of mergeDocuments function: origins of values,
+// which are replaced by override document, are removed...
+func (o documentOrigins) Merge(override documentOrigins) {
+	for nodePointer := range o {
+		if override.isReplaced(nodePointer) {
+			delete(o, nodePointer)
+		}
+	}
+
+	for nodePointer, origin := range override {
+		o[nodePointer] = origin
+	}
+}
+
+// Locate - set file path, line and column of decode error by origin of value, which is referenced by pointer
+// of error. Position of error is cleared, if origin of value is unknown...
+func (o documentOrigins) Locate(err error) error {
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		return err
+	}
+
+	origin, isFound := o.lookup(decodeErr.Pointer)
+	if !isFound {
+		return withoutPosition(err)
+	}
+
+	locatedErr := newDecodeError(decodeErr.Cause, origin.sourceData, origin.offset)
+	decodeErr.File = origin.file
+	decodeErr.Line, decodeErr.Column, decodeErr.Offset = locatedErr.Line, locatedErr.Column, locatedErr.Offset
+
+	return err
+}
+
+// lookup - search origin of value by pointer or origin of its closest parent...
+func (o documentOrigins) lookup(pointer string) (nodeOrigin, bool) {
+	for {
+		origin, isExists := o[pointer]
+		if isExists {
+			return origin, true
+		}
+
+		separatorIndex := strings.LastIndexByte(pointer, '/')
+		if separatorIndex < 0 {
+			return nodeOrigin{}, false
+		}
+
+		pointer = pointer[:separatorIndex]
+	}
+}
+
+// isReplaced - check that value by pointer is replaced by value of override document:
+// value by same pointer or non-object value of parent exists in override document...
+func (o documentOrigins) isReplaced(pointer string) bool {
+	if _, isExists := o[pointer]; isExists {
+		return true
+	}
+
+	for separatorIndex := strings.LastIndexByte(pointer, '/'); separatorIndex >= 0; {
+		pointer = pointer[:separatorIndex]
+
+		origin, isExists := o[pointer]
+		if isExists && !origin.isObject() {
+			return true
+		}
+
+		separatorIndex = strings.LastIndexByte(pointer, '/')
+	}
+
+	return false
+}
+
+// isPointerInside - check that pointer references value by parent pointer or its nested value...
+func isPointerInside(pointer, parentPointer string) bool {
+	return pointer == parentPointer || strings.HasPrefix(pointer, parentPointer+"/")
+}
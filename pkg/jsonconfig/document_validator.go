@@ -0,0 +1,565 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mailru/easyjson"
+)
+
+const (
+	typeObject  = "object"
+	typeArray   = "array"
+	typeString  = "string"
+	typeBoolean = "boolean"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeNull    = "null"
+
+	// metaKeyPrefix - prefix of meta keys, e.g. $min_version. Meta keys are allowed in strict mode...
+	metaKeyPrefix = "$"
+
+	// maxSuggestionDistance - maximal edit distance of unknown key and suggested known key...
+	maxSuggestionDistance = 2
+)
+
+var (
+	jsonUnmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType     = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	easyjsonUnmarshalerType = reflect.TypeOf((*easyjson.Unmarshaler)(nil)).Elem()
+)
+
+// documentValidator - position-aware validation of JSON document by type of config struct.
+// Validator reports all type errors and unknown keys (in strict mode) with line, column and JSON pointer,
+// syntax error stops validation. Document is validated before decoding by easyjson, which reports
+// only first error without position...
+type documentValidator struct {
+	// data - document for validation, e.g. JSONC document with stripped comments...
+	data []byte
+	// sourceData - source document, used for calculation of lines and columns...
+	sourceData []byte
+
+	errorsList  []error
+	fieldsCache map[reflect.Type]map[string]reflect.Type
+	// offsetsMap - optional map of offsets of values by JSON pointers, filled by validation...
+	offsetsMap     map[string]int
+	pos            int
+	isUnknownError bool
+}
+
+// Validate - validate document by passed type. Returns list of type and unknown keys errors in order of document.
+// Syntax error returned as second value...
+func (v *documentValidator) Validate(targetType reflect.Type) ([]error, error) {
+	v.skipWhitespace()
+
+	err := v.validateValue(targetType, "")
+	if err != nil {
+		return nil, err
+	}
+
+	v.skipWhitespace()
+
+	if v.pos < len(v.data) {
+		return nil, v.newError(fmt.Errorf("%w: unexpected data after top-level value", ErrInvalidJSON), v.pos)
+	}
+
+	return v.errorsList, nil
+}
+
+//nolint:cyclop // it's ok, switch by JSON value type
+func (v *documentValidator) validateValue(targetType reflect.Type, pointer string) error {
+	if v.pos >= len(v.data) {
+		return v.newError(fmt.Errorf("%w: unexpected end of document", ErrInvalidJSON), v.pos)
+	}
+
+	expected := expectedTypeName(targetType)
+	start := v.pos
+
+	if v.offsetsMap != nil {
+		v.offsetsMap[pointer] = start
+	}
+
+	switch v.data[v.pos] {
+	case '{':
+		if expected != typeObject && expected != "" {
+			v.addTypeError(expected, typeObject, pointer, start)
+
+			return v.validateObject(nil, pointer)
+		}
+
+		return v.validateObject(targetType, pointer)
+	case '[':
+		if expected != typeArray && expected != "" {
+			v.addTypeError(expected, typeArray, pointer, start)
+
+			return v.validateArray(nil, pointer)
+		}
+
+		return v.validateArray(targetType, pointer)
+	case '"':
+		err := v.skipString()
+		if err == nil && expected != typeString && expected != "" {
+			v.addTypeError(expected, typeString, pointer, start)
+		}
+
+		return err
+	case 't', 'f':
+		err := v.skipLiteral()
+		if err == nil && expected != typeBoolean && expected != "" {
+			v.addTypeError(expected, typeBoolean, pointer, start)
+		}
+
+		return err
+	case 'n':
+		// null values are skipped by easyjson for any type
+		return v.skipLiteral()
+	default:
+		return v.validateNumber(targetType, expected, pointer)
+	}
+}
+
+func (v *documentValidator) validateObject(targetType reflect.Type, pointer string) error {
+	fieldsMap := v.objectFields(targetType)
+	v.pos++ // {
+	v.skipWhitespace()
+
+	if v.pos < len(v.data) && v.data[v.pos] == '}' {
+		v.pos++
+
+		return nil
+	}
+
+	for {
+		v.skipWhitespace()
+
+		keyStart := v.pos
+		if keyStart >= len(v.data) || v.data[keyStart] != '"' {
+			return v.newError(fmt.Errorf("%w: expected object key", ErrInvalidJSON), keyStart)
+		}
+
+		err := v.skipString()
+		if err != nil {
+			return err
+		}
+
+		key, err := strconv.Unquote(string(v.data[keyStart:v.pos]))
+		if err != nil {
+			return v.newError(fmt.Errorf("%w: wrong object key", ErrInvalidJSON), keyStart)
+		}
+
+		v.skipWhitespace()
+
+		if v.pos >= len(v.data) || v.data[v.pos] != ':' {
+			return v.newError(fmt.Errorf("%w: expected colon after object key", ErrInvalidJSON), v.pos)
+		}
+
+		v.pos++
+		v.skipWhitespace()
+
+		keyPointer := pointer + "/" + escapeJSONPointerToken(key)
+		valueType := v.lookupKeyType(targetType, fieldsMap, key, keyPointer, keyStart)
+
+		err = v.validateValue(valueType, keyPointer)
+		if err != nil {
+			return err
+		}
+
+		isEnd, err := v.nextItem('}')
+		if err != nil || isEnd {
+			return err
+		}
+	}
+}
+
+func (v *documentValidator) validateArray(targetType reflect.Type, pointer string) error {
+	var itemType reflect.Type
+	if targetType != nil {
+		itemType = derefType(targetType).Elem()
+	}
+
+	v.pos++ // [
+	v.skipWhitespace()
+
+	if v.pos < len(v.data) && v.data[v.pos] == ']' {
+		v.pos++
+
+		return nil
+	}
+
+	for i := 0; ; i++ {
+		v.skipWhitespace()
+
+		err := v.validateValue(itemType, pointer+"/"+strconv.Itoa(i))
+		if err != nil {
+			return err
+		}
+
+		isEnd, err := v.nextItem(']')
+		if err != nil || isEnd {
+			return err
+		}
+	}
+}
+
+func (v *documentValidator) validateNumber(targetType reflect.Type, expected, pointer string) error {
+	start := v.pos
+
+	for v.pos < len(v.data) && strings.IndexByte("+-0123456789.eE", v.data[v.pos]) >= 0 {
+		v.pos++
+	}
+
+	rawNumber := string(v.data[start:v.pos])
+	if rawNumber == "" || !json.Valid([]byte(rawNumber)) {
+		return v.newError(fmt.Errorf("%w: unexpected symbol", ErrInvalidJSON), start)
+	}
+
+	switch expected {
+	case "", typeNumber:
+		return nil
+	case typeInteger:
+	default:
+		v.addTypeError(expected, typeNumber, pointer, start)
+
+		return nil
+	}
+
+	if strings.ContainsAny(rawNumber, ".eE") {
+		v.addTypeError(expected, typeNumber, pointer, start)
+
+		return nil
+	}
+
+	targetType = derefType(targetType)
+
+	var err error
+
+	switch targetType.Kind() { //nolint:exhaustive // it's ok, only integer kinds are possible
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		_, err = strconv.ParseUint(rawNumber, 10, targetType.Bits())
+	default:
+		_, err = strconv.ParseInt(rawNumber, 10, targetType.Bits())
+	}
+
+	if err != nil {
+		decodeErr := v.newError(fmt.Errorf("%w: %s of %s", ErrValueOutOfRange, rawNumber, targetType), start)
+		decodeErr.Pointer = pointer
+		decodeErr.Expected = expected
+
+		v.errorsList = append(v.errorsList, decodeErr)
+	}
+
+	return nil
+}
+
+// lookupKeyType returns type of object value by key. Unknown keys of struct are reported in strict mode...
+func (v *documentValidator) lookupKeyType(targetType reflect.Type,
+	fieldsMap map[string]reflect.Type,
+	key, keyPointer string,
+	keyStart int,
+) reflect.Type {
+	if fieldsMap == nil {
+		if targetType == nil || derefType(targetType).Kind() != reflect.Map {
+			return nil
+		}
+
+		return derefType(targetType).Elem()
+	}
+
+	fieldType, isKnown := fieldsMap[key]
+	if isKnown || strings.HasPrefix(key, metaKeyPrefix) {
+		return fieldType
+	}
+
+	if v.isUnknownError {
+		cause := fmt.Errorf("%w: %s", ErrUnknownKey, key)
+
+		suggestion := suggestKey(key, fieldsMap)
+		if suggestion != "" {
+			cause = fmt.Errorf("%w: %s, did you mean %s?", ErrUnknownKey, key, suggestion)
+		}
+
+		decodeErr := v.newError(cause, keyStart)
+		decodeErr.Pointer = keyPointer
+
+		v.errorsList = append(v.errorsList, decodeErr)
+	}
+
+	return nil
+}
+
+// objectFields returns map of JSON keys and types of struct fields. Returns nil for non-struct types...
+func (v *documentValidator) objectFields(targetType reflect.Type) map[string]reflect.Type {
+	if targetType == nil || derefType(targetType).Kind() != reflect.Struct {
+		return nil
+	}
+
+	structType := derefType(targetType)
+
+	fieldsMap, isCached := v.fieldsCache[structType]
+	if isCached {
+		return fieldsMap
+	}
+
+	fieldsMap = make(map[string]reflect.Type)
+	collectStructFields(structType, fieldsMap)
+	v.fieldsCache[structType] = fieldsMap
+
+	return fieldsMap
+}
+
+// nextItem - consume comma or closing delimiter of object or array...
+func (v *documentValidator) nextItem(closingDelim byte) (bool, error) {
+	v.skipWhitespace()
+
+	if v.pos >= len(v.data) {
+		return false, v.newError(fmt.Errorf("%w: unexpected end of document", ErrInvalidJSON), v.pos)
+	}
+
+	switch v.data[v.pos] {
+	case ',':
+		v.pos++
+
+		return false, nil
+	case closingDelim:
+		v.pos++
+
+		return true, nil
+	default:
+		return false, v.newError(fmt.Errorf("%w: expected comma or %q", ErrInvalidJSON, closingDelim), v.pos)
+	}
+}
+
+func (v *documentValidator) skipString() error {
+	start := v.pos
+	end := skipJSONString(v.data, start)
+
+	if end >= len(v.data) {
+		return v.newError(fmt.Errorf("%w: unterminated string", ErrInvalidJSON), start)
+	}
+
+	v.pos = end + 1
+
+	return nil
+}
+
+func (v *documentValidator) skipLiteral() error {
+	for _, literal := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(string(v.data[v.pos:min(v.pos+len(literal), len(v.data))]), literal) {
+			v.pos += len(literal)
+
+			return nil
+		}
+	}
+
+	return v.newError(fmt.Errorf("%w: unexpected symbol", ErrInvalidJSON), v.pos)
+}
+
+func (v *documentValidator) skipWhitespace() {
+	for v.pos < len(v.data) {
+		switch v.data[v.pos] {
+		case ' ', '\t', '\n', '\r':
+			v.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (v *documentValidator) addTypeError(expected, actual, pointer string, offset int) {
+	decodeErr := v.newError(fmt.Errorf("%w: expected %s, got %s", ErrUnexpectedType, expected, actual), offset)
+	decodeErr.Pointer = pointer
+	decodeErr.Expected = expected
+
+	v.errorsList = append(v.errorsList, decodeErr)
+}
+
+func (v *documentValidator) newError(cause error, offset int) *DecodeError {
+	return newDecodeError(cause, v.sourceData, offset)
+}
+
+// collectStructFields - collect JSON keys of struct fields by easyjson rules: json tag name or field name,
+// fields of embedded structs without json tag are promoted...
+func collectStructFields(structType reflect.Type, fieldsMap map[string]reflect.Type) {
+	for i := range structType.NumField() {
+		structField := structType.Field(i)
+
+		tagValue := structField.Tag.Get("json")
+		if tagValue == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tagValue, ",")
+
+		if structField.Anonymous && name == "" && derefType(structField.Type).Kind() == reflect.Struct {
+			collectStructFields(derefType(structField.Type), fieldsMap)
+
+			continue
+		}
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = structField.Name
+		}
+
+		fieldType := structField.Type
+		if strings.Contains(options, "string") {
+			// value encoded as JSON string, e.g. `json:",string"`
+			fieldType = nil
+		}
+
+		fieldsMap[name] = fieldType
+	}
+}
+
+// expectedTypeName returns name of expected JSON type. Empty name means that any value is allowed...
+//
+//nolint:cyclop // it's ok, switch by kind
+func expectedTypeName(targetType reflect.Type) string {
+	if targetType == nil {
+		return ""
+	}
+
+	targetType = derefType(targetType)
+	if hasCustomUnmarshaler(targetType) {
+		return ""
+	}
+
+	switch targetType.Kind() { //nolint:exhaustive // it's ok, other kinds are not supported by easyjson
+	case reflect.Struct, reflect.Map:
+		return typeObject
+	case reflect.Slice:
+		if targetType.Elem().Kind() == reflect.Uint8 {
+			// base64 encoded string
+			return typeString
+		}
+
+		return typeArray
+	case reflect.Array:
+		return typeArray
+	case reflect.String:
+		return typeString
+	case reflect.Bool:
+		return typeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return typeInteger
+	case reflect.Float32, reflect.Float64:
+		return typeNumber
+	default:
+		return ""
+	}
+}
+
+// hasCustomUnmarshaler - check that type has own unmarshal function. Structs with generated easyjson
+// unmarshal function are validated by fields...
+func hasCustomUnmarshaler(targetType reflect.Type) bool {
+	pointerType := reflect.PointerTo(targetType)
+	isEasyJSON := pointerType.Implements(easyjsonUnmarshalerType)
+
+	if targetType.Kind() == reflect.Struct && isEasyJSON {
+		return false
+	}
+
+	return isEasyJSON || pointerType.Implements(jsonUnmarshalerType) || pointerType.Implements(textUnmarshalerType)
+}
+
+func derefType(targetType reflect.Type) reflect.Type {
+	for targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+
+	return targetType
+}
+
+// suggestKey returns known key with minimal edit distance to unknown key...
+func suggestKey(key string, fieldsMap map[string]reflect.Type) string {
+	suggestion := ""
+	minDistance := maxSuggestionDistance + 1
+
+	for knownKey := range fieldsMap {
+		distance := editDistance(key, knownKey)
+		if distance < minDistance || (distance == minDistance && knownKey < suggestion) {
+			suggestion, minDistance = knownKey, distance
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance - Levenshtein distance of strings...
+func editDistance(left, right string) int {
+	previousRow := make([]int, len(right)+1)
+	currentRow := make([]int, len(right)+1)
+
+	for j := range previousRow {
+		previousRow[j] = j
+	}
+
+	for i := 1; i <= len(left); i++ {
+		currentRow[0] = i
+
+		for j := 1; j <= len(right); j++ {
+			substitutionCost := 1
+			if left[i-1] == right[j-1] {
+				substitutionCost = 0
+			}
+
+			currentRow[j] = min(previousRow[j]+1, currentRow[j-1]+1, previousRow[j-1]+substitutionCost)
+		}
+
+		previousRow, currentRow = currentRow, previousRow
+	}
+
+	return previousRow[len(right)]
+}
+
+func newDocumentValidator(data, sourceData []byte, isUnknownError bool) *documentValidator {
+	return &documentValidator{
+		data:           data,
+		sourceData:     sourceData,
+		errorsList:     make([]error, 0),
+		fieldsCache:    make(map[reflect.Type]map[string]reflect.Type),
+		offsetsMap:     nil,
+		pos:            0,
+		isUnknownError: isUnknownError,
+	}
+}
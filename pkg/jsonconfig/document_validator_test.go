@@ -0,0 +1,140 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestServiceDecodeErrors(t *testing.T) {
+	rawData := []byte(`{
+  "top_level_field_int": -1,
+  "list": [
+    {"int_field_one": 1.5, "db_port": "1"},
+    {"int_field_one": 2, "db_port": 5432, "float_field": "fast"},
+    "item"
+  ]
+}`)
+
+	err := NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFrom(rawData).Do(context.Background())
+	if err == nil {
+		t.Errorf("expected decode errors")
+		return
+	}
+
+	var joinedErr interface{ Unwrap() []error }
+	if !errors.As(err, &joinedErr) {
+		t.Errorf("expected aggregated errors: %v", err)
+		return
+	}
+
+	expectedList := []struct {
+		pointer  string
+		expected string
+		line     int
+		column   int
+	}{
+		{"/top_level_field_int", typeInteger, 2, 26},
+		{"/list/0/int_field_one", typeInteger, 4, 23},
+		{"/list/1/db_port", typeString, 5, 37},
+		{"/list/1/float_field", typeNumber, 5, 58},
+		{"/list/2", typeObject, 6, 5},
+	}
+
+	errorsList := joinedErr.Unwrap()
+	if len(errorsList) != len(expectedList) {
+		t.Errorf("not equal count of errors: %v", err)
+		return
+	}
+
+	for i, expected := range expectedList {
+		var decodeErr *DecodeError
+		if !errors.As(errorsList[i], &decodeErr) {
+			t.Errorf("expected decode error: %v", errorsList[i])
+			continue
+		}
+
+		if decodeErr.Pointer != expected.pointer || decodeErr.Expected != expected.expected ||
+			decodeErr.Line != expected.line || decodeErr.Column != expected.column {
+			t.Errorf("not equal decode error: %s", decodeErr)
+		}
+	}
+
+	if !errors.Is(errorsList[0], ErrValueOutOfRange) || !errors.Is(errorsList[1], ErrUnexpectedType) {
+		t.Errorf("not equal causes of errors: %v", err)
+	}
+
+	err = NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFrom([]byte("{\"list\": [}")).Do(context.Background())
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || !errors.Is(err, ErrInvalidJSON) || decodeErr.Column != 11 {
+		t.Errorf("expected syntax error: %v", err)
+	}
+}
+
+func TestServiceStrictKeys(t *testing.T) {
+	rawData := []byte(`{
+  "$min_version": "0.0.1",
+  "int_field_one": 1,
+  "int_field_two": 2,
+  "db_port": "5432",
+  "unknown": true
+}`)
+
+	err := NewService(nil).PrepareTo(&SimpleJSONCase{}).PrepareFrom(rawData).
+		With(&mockReleaseVersionProvider{releaseTag: "1.0.0"}).Do(context.Background())
+	if err != nil {
+		t.Errorf("unknown keys must be ignored without strict mode: %s", err)
+		return
+	}
+
+	err = NewService(nil).WithStrictKeys().PrepareTo(&SimpleJSONCase{}).PrepareFrom(rawData).
+		With(&mockReleaseVersionProvider{releaseTag: "1.0.0"}).Do(context.Background())
+	if !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected unknown key error: %v", err)
+		return
+	}
+
+	var joinedErr interface{ Unwrap() []error }
+	if !errors.As(err, &joinedErr) || len(joinedErr.Unwrap()) != 2 {
+		t.Errorf("expected two unknown keys: %v", err)
+		return
+	}
+
+	if !strings.Contains(err.Error(), "4:3: /int_field_two: unknown key: int_field_two, did you mean int_field_tow?") {
+		t.Errorf("expected suggestion of known key: %s", err)
+	}
+}
@@ -0,0 +1,376 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+const (
+	envSourceName     = "env"
+	defaultSourceName = "default"
+)
+
+// fieldsFiller - applies envconfig, default, required and required_in tags of config struct fields
+// after decoding of JSON document. Priority of values:
+//   - variable from sources in dependencies list or process environment by envconfig key
+//   - value of JSON document
+//   - default value, if key is absent in JSON document or has null value
+//
+// Field is required if after all steps key is absent, null or empty and field value wasn't overridden by variable...
+type fieldsFiller struct {
+	e      errorFormatterService
+	logger *common.Logger
+
+	target              interface{}
+	variableSourcesList []variableSourceService
+	environmentName     string
+	environmentNames    map[string]string
+}
+
+func (f *fieldsFiller) Process(rawJSONData []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(rawJSONData))
+	decoder.UseNumber()
+
+	var document interface{}
+
+	err := decoder.Decode(&document)
+	if err != nil {
+		return f.e.ErrorOnly(err)
+	}
+
+	targetValue := reflect.ValueOf(f.target)
+
+	return f.processFields(targetValue, reflect.Indirect(targetValue).Type().Name(), document)
+}
+
+//nolint:cyclop // it's ok, linear flow of value sources
+func (f *fieldsFiller) processFields(target reflect.Value, path string, documentNode interface{}) error {
+	element := reflect.Indirect(target)
+	if element.Kind() != reflect.Struct {
+		return nil
+	}
+
+	objectNode, _ := documentNode.(map[string]interface{})
+	elemType := element.Type()
+
+	for i := range elemType.NumField() {
+		structField := elemType.Field(i)
+		fieldPath := path + "." + structField.Name
+
+		fieldValue := element.Field(i)
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		isIgnored, _ := strconv.ParseBool(structField.Tag.Get(common.TagIgnored))
+		if isIgnored {
+			continue
+		}
+
+		var fieldNode interface{} = objectNode
+
+		isKeyExists := objectNode != nil
+		if !structField.Anonymous {
+			fieldNode, isKeyExists = lookupJSONKey(objectNode, structField)
+		}
+
+		err := f.processNested(fieldValue, fieldPath, fieldNode)
+		if err != nil {
+			return err
+		}
+
+		if isNestedKind(fieldValue) {
+			continue
+		}
+
+		err = f.processField(structField, fieldValue, fieldPath, fieldNode, isKeyExists)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processNested - process structs, pointers to structs and slices of structs. Nil pointer to struct
+// is allocated only if key of JSON document has non-null value or struct has fields with variable override,
+// default value or required tag, so optional sections keep nil value if key is absent...
+func (f *fieldsFiller) processNested(fieldValue reflect.Value, fieldPath string, fieldNode interface{}) error {
+	if !isNestedKind(fieldValue) {
+		return nil
+	}
+
+	switch fieldValue.Kind() {
+	case reflect.Struct:
+		return f.processFields(fieldValue, fieldPath, fieldNode)
+	case reflect.Ptr:
+		if fieldValue.IsNil() {
+			if fieldNode == nil && !f.hasTaggedFields(fieldValue.Type().Elem()) {
+				return nil
+			}
+
+			fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+		}
+
+		return f.processFields(fieldValue, fieldPath, fieldNode)
+	case reflect.Slice:
+		arrayNode, _ := fieldNode.([]interface{})
+
+		for j := range fieldValue.Len() {
+			var itemNode interface{}
+			if j < len(arrayNode) {
+				itemNode = arrayNode[j]
+			}
+
+			err := f.processFields(fieldValue.Index(j), fieldPath+"["+strconv.Itoa(j)+"]", itemNode)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return nil
+	}
+}
+
+// hasTaggedFields - check that struct type or nested struct types have fields with variable override,
+// default value or required tag, which must be applied for absent or null key...
+func (f *fieldsFiller) hasTaggedFields(structType reflect.Type) bool {
+	for i := range structType.NumField() {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		isIgnored, _ := strconv.ParseBool(structField.Tag.Get(common.TagIgnored))
+		if isIgnored {
+			continue
+		}
+
+		envConfigKey := structField.Tag.Get(common.TagEnvconfig)
+		if envConfigKey != "" {
+			_, _, isExists := f.lookupVariable(envConfigKey)
+			if isExists {
+				return true
+			}
+		}
+
+		_, hasDefaultValue := common.LookupDefault(structField.Tag, f.environmentName, f.environmentNames)
+		if hasDefaultValue {
+			return true
+		}
+
+		isRequired, err := common.IsRequired(structField.Tag, f.environmentName, f.environmentNames)
+		if isRequired || err != nil {
+			return true
+		}
+
+		fieldType := structField.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && !common.IsTextUnmarshaler(structField.Type) &&
+			f.hasTaggedFields(fieldType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *fieldsFiller) processField(structField reflect.StructField,
+	fieldValue reflect.Value,
+	fieldPath string,
+	fieldNode interface{},
+	isKeyExists bool,
+) error {
+	envConfigKey := structField.Tag.Get(common.TagEnvconfig)
+	isSecret, _ := strconv.ParseBool(structField.Tag.Get(common.TagSecret))
+	isSensitive := isSecret || common.IsScrubField(structField.Tag)
+
+	commonField := common.Field{
+		Name:      structField.Name,
+		EnvKey:    envConfigKey,
+		SecretKey: "",
+		Source:    "",
+		RfValue:   fieldValue,
+		RfTags:    structField.Tag,
+		Value:     "",
+	}
+
+	if envConfigKey != "" {
+		value, sourceName, isExists := f.lookupVariable(envConfigKey)
+		if isExists {
+			commonField.Source, commonField.Value = sourceName, value
+
+			err := common.SetField(value, fieldValue)
+			if err != nil {
+				return f.e.ErrorNoWrap(common.NewFieldError(err, fieldPath, commonField, isSensitive))
+			}
+
+			f.logger.FieldResolved(fieldPath, envConfigKey, sourceName, value, isSensitive)
+
+			return nil
+		}
+	}
+
+	if isKeyExists && fieldNode != nil {
+		return f.checkRequired(commonField, fieldPath, isEmptyJSONValue(fieldNode), isSensitive)
+	}
+
+	defaultValue, hasDefaultValue := common.LookupDefault(structField.Tag, f.environmentName, f.environmentNames)
+	if !hasDefaultValue {
+		return f.checkRequired(commonField, fieldPath, true, isSensitive)
+	}
+
+	commonField.Source, commonField.Value = defaultSourceName, defaultValue
+
+	err := common.SetField(defaultValue, fieldValue)
+	if err != nil {
+		return f.e.ErrorNoWrap(common.NewFieldError(err, fieldPath, commonField, isSensitive))
+	}
+
+	f.logger.DefaultApplied(fieldPath, envConfigKey, defaultValue, isSensitive)
+
+	return f.checkRequired(commonField, fieldPath, defaultValue == "", isSensitive)
+}
+
+func (f *fieldsFiller) checkRequired(field common.Field, fieldPath string, isEmpty, isSensitive bool) error {
+	if !isEmpty {
+		return nil
+	}
+
+	isRequired, err := common.IsRequired(field.RfTags, f.environmentName, f.environmentNames)
+	if err != nil {
+		return f.e.ErrorNoWrap(common.NewFieldError(&common.ParseError{
+			Cause: err,
+			Value: field.RfTags.Get(common.TagRequired),
+			Type:  "bool",
+		}, fieldPath, field, isSensitive))
+	}
+
+	if !isRequired {
+		return nil
+	}
+
+	return f.e.ErrorNoWrap(common.NewFieldError(&common.ValidationError{
+		Cause: ErrVariableEmptyButRequired,
+		Rule:  common.ValidationRuleRequired,
+	}, fieldPath, field, isSensitive))
+}
+
+// lookupVariable - search variable value in variable sources of dependencies list and after that
+// in process environment. Returns value and name of source, which provided the value...
+func (f *fieldsFiller) lookupVariable(key string) (string, string, bool) {
+	for _, sourceSvc := range f.variableSourcesList {
+		value, isExists := sourceSvc.LookupValue(key)
+		if isExists {
+			return value, sourceSvc.GetSourceName(), true
+		}
+	}
+
+	value, isExists := os.LookupEnv(key)
+
+	return value, envSourceName, isExists
+}
+
+// lookupJSONKey - search value of struct field in JSON object by name from json tag or by field name.
+// Keys are matched exactly, same as easyjson decoder does...
+func lookupJSONKey(objectNode map[string]interface{}, structField reflect.StructField) (interface{}, bool) {
+	if objectNode == nil {
+		return nil, false
+	}
+
+	keyName, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+
+	switch keyName {
+	case "-":
+		return nil, false
+	case "":
+		keyName = structField.Name
+	default:
+	}
+
+	value, isExists := objectNode[keyName]
+
+	return value, isExists
+}
+
+// isNestedKind - check that field value is processed as nested config, not as single value.
+// Structs with UnmarshalText function, e.g. config.Rate, are single values...
+func isNestedKind(fieldValue reflect.Value) bool {
+	if common.IsTextUnmarshaler(fieldValue.Type()) {
+		return false
+	}
+
+	switch fieldValue.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Ptr:
+		return fieldValue.Type().Elem().Kind() == reflect.Struct
+	case reflect.Slice:
+		itemType := fieldValue.Type().Elem()
+		if itemType.Kind() == reflect.Ptr {
+			itemType = itemType.Elem()
+		}
+
+		return itemType.Kind() == reflect.Struct && !common.IsTextUnmarshaler(itemType)
+	default:
+		return false
+	}
+}
+
+func isEmptyJSONValue(value interface{}) bool {
+	switch castedValue := value.(type) {
+	case nil:
+		return true
+	case string:
+		return castedValue == ""
+	case []interface{}:
+		return len(castedValue) == 0
+	case map[string]interface{}:
+		return len(castedValue) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/config"
+)
+
+type mockVariableSource struct {
+	valuesPool map[string]string
+}
+
+func (s *mockVariableSource) LookupValue(key string) (string, bool) {
+	value, isExists := s.valuesPool[key]
+
+	return value, isExists
+}
+
+func (s *mockVariableSource) GetSourceName() string {
+	return "mock"
+}
+
+func TestServiceFieldsTags(t *testing.T) {
+	t.Setenv("TAGGED_CASE_HOST", "env-host")
+
+	cfg := &taggedJSONCase{}
+
+	err := NewService(nil).PrepareTo(cfg).
+		PrepareFrom([]byte(`{"host": "file-host", "retries": 0, "nodes": [{"name": "a", "timeout": null}],
+			"limits": {"max_count": 5}}`)).
+		With(&mockVariableSource{valuesPool: map[string]string{"TAGGED_CASE_MAX_COUNT": "20"}}).
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.Host != "env-host" || cfg.Port != 8080 || cfg.Retries != 0 || cfg.Limits.MaxCount != 20 {
+		t.Errorf("not equal filled config: %+v", cfg)
+	}
+
+	if cfg.NodesList[0].Timeout != 5*time.Second {
+		t.Errorf("expected default value of null key: %s", cfg.NodesList[0].Timeout)
+	}
+
+	cfg = &taggedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).
+		PrepareFrom([]byte(`{"token": "value", "nodes": [{"name": "a"}]}`)).
+		WithEnvironment("production").
+		Do(context.Background())
+	if err != nil || cfg.NodesList[0].Timeout != 30*time.Second {
+		t.Errorf("expected environment-specific default: %v", err)
+	}
+
+	cfg = &taggedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).PrepareFrom([]byte(`{"nodes": []}`)).Do(context.Background())
+	if err != nil || cfg.Limits == nil || cfg.Limits.MaxCount != 10 {
+		t.Errorf("expected default value of struct with absent key: %+v, %v", cfg.Limits, err)
+	}
+
+	cfg = &taggedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).PrepareFrom([]byte(`{"limits": null}`)).
+		With(&mockVariableSource{valuesPool: map[string]string{"TAGGED_CASE_MAX_COUNT": "30"}}).
+		Do(context.Background())
+	if err != nil || cfg.Limits == nil || cfg.Limits.MaxCount != 30 {
+		t.Errorf("expected variable value of struct with null key: %+v, %v", cfg.Limits, err)
+	}
+
+	cfg = &taggedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).PrepareFrom([]byte(`{"host": "h", "PORT": 1, "nodes": []}`)).
+		Do(context.Background())
+	if err != nil || cfg.Port != 8080 {
+		t.Errorf("expected default value of key with different case: %d, %v", cfg.Port, err)
+	}
+}
+
+func TestServiceFieldsOptionalSection(t *testing.T) {
+	cfg := &nestedSecretsJSONCase{}
+
+	err := NewService(nil).PrepareTo(cfg).PrepareFrom([]byte(`{"tls": null}`)).
+		With(&mockSecretManager{ValuesPool: map[string]string{}}).
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.TLS != nil {
+		t.Errorf("optional section of null key must be nil: %+v", cfg.TLS)
+	}
+
+	cfg = &nestedSecretsJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).PrepareFrom([]byte(`{}`)).
+		With(&mockSecretManager{ValuesPool: map[string]string{}}).
+		Do(context.Background())
+	if err != nil || cfg.TLS != nil {
+		t.Errorf("optional section of absent key must be nil: %+v, %v", cfg.TLS, err)
+	}
+}
+
+func TestServiceFieldsRequired(t *testing.T) {
+	testCases := []struct {
+		name            string
+		environmentName string
+		rawData         string
+		expectedPath    string
+	}{
+		{
+			name:            "absent key",
+			environmentName: "",
+			rawData:         `{"nodes": [{"name": "a"}, {"timeout": 1000}]}`,
+			expectedPath:    "taggedJSONCase.NodesList[1].Name",
+		},
+		{
+			name:            "empty value",
+			environmentName: "",
+			rawData:         `{"nodes": [{"name": ""}]}`,
+			expectedPath:    "taggedJSONCase.NodesList[0].Name",
+		},
+		{
+			name:            "required in environment",
+			environmentName: "production",
+			rawData:         `{"token": null}`,
+			expectedPath:    "taggedJSONCase.Token",
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := NewService(nil).PrepareTo(&taggedJSONCase{}).PrepareFrom([]byte(testCase.rawData)).
+			WithEnvironment(testCase.environmentName).
+			Do(context.Background())
+
+		var fieldErr *common.FieldError
+		if !errors.As(err, &fieldErr) || !errors.Is(err, ErrVariableEmptyButRequired) {
+			t.Errorf("%s: expected field error of required rule: %v", testCase.name, err)
+
+			continue
+		}
+
+		if fieldErr.Path != testCase.expectedPath {
+			t.Errorf("%s: not equal field path: %s", testCase.name, fieldErr.Path)
+		}
+	}
+
+	err := NewService(nil).PrepareTo(&taggedJSONCase{}).PrepareFrom([]byte(`{"token": ""}`)).
+		WithEnvironment("development").
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("token isn't required in development environment: %s", err)
+	}
+}
+
+func TestServiceValueTypes(t *testing.T) {
+	cfg := &valueTypesJSONCase{}
+
+	err := NewService(nil).PrepareTo(cfg).
+		PrepareFrom([]byte(`{"max_block_size": "4MiB", "multiplier": "115%", "rpc_rate": "10/s",
+			"rates": ["5/100ms", "1000/h"]}`)).
+		With(&mockVariableSource{valuesPool: map[string]string{"VALUE_TYPES_CASE_RPC_RATE": "50/s"}}).
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.MaxBlockSize != 4*config.MiB || cfg.CacheSize != 64*config.MiB || cfg.Multiplier != 115 ||
+		cfg.RPCRate != (config.Rate{Count: 50, Per: time.Second}) || len(cfg.RatesList) != 2 ||
+		cfg.RatesList[1] != (config.Rate{Count: 1000, Per: time.Hour}) {
+		t.Errorf("not equal filled config: %+v", cfg)
+	}
+
+	err = NewService(nil).PrepareTo(&valueTypesJSONCase{}).
+		PrepareFrom([]byte(`{"max_block_size": "16EiB"}`)).
+		Do(context.Background())
+	if !errors.Is(err, config.ErrByteSizeOverflow) {
+		t.Errorf("expected overflow error: %v", err)
+	}
+}
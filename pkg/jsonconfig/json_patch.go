@@ -0,0 +1,437 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrWrongJSONPatch          = errors.New("wrong JSON patch")
+	ErrJSONPatchTestFailed     = errors.New("JSON patch test operation failed")
+	ErrJSONPointerIsNotFound   = errors.New("JSON pointer target is not found")
+	ErrWrongJSONPointerElement = errors.New("wrong JSON pointer element")
+)
+
+const (
+	patchOpAdd     = "add"
+	patchOpRemove  = "remove"
+	patchOpReplace = "replace"
+	patchOpMove    = "move"
+	patchOpCopy    = "copy"
+	patchOpTest    = "test"
+
+	// appendIndex - JSON pointer element of position after last array item...
+	appendIndex = "-"
+)
+
+// applyMergePatch - apply JSON Merge Patch (RFC 7396) to document. Null values of patch remove keys of document...
+func applyMergePatch(document, patch interface{}) interface{} {
+	patchObject, isPatchObject := patch.(map[string]interface{})
+	if !isPatchObject {
+		return patch
+	}
+
+	documentObject, isDocumentObject := document.(map[string]interface{})
+	if !isDocumentObject {
+		documentObject = make(map[string]interface{}, len(patchObject))
+	}
+
+	for key, patchValue := range patchObject {
+		if patchValue == nil {
+			delete(documentObject, key)
+
+			continue
+		}
+
+		documentObject[key] = applyMergePatch(documentObject[key], patchValue)
+	}
+
+	return documentObject
+}
+
+// applyJSONPatch - apply JSON Patch (RFC 6902) operations to document. Operations are applied in order,
+// first failed operation stops processing. Origins patcher is optional...
+func applyJSONPatch(document interface{},
+	operationsList []interface{},
+	originsPatcherSvc *originsPatcher,
+) (interface{}, error) {
+	for i, rawOperation := range operationsList {
+		operation, isObject := rawOperation.(map[string]interface{})
+		if !isObject {
+			return nil, fmt.Errorf("%w: operation %d must be an object", ErrWrongJSONPatch, i)
+		}
+
+		opName, _ := operation["op"].(string)
+		path, isPathString := operation["path"].(string)
+
+		if !isPathString {
+			return nil, fmt.Errorf("%w: operation %d: path must be a string", ErrWrongJSONPatch, i)
+		}
+
+		if originsPatcherSvc != nil {
+			from, _ := operation["from"].(string)
+			originsPatcherSvc.PatchOperation(document, i, opName, path, from)
+		}
+
+		var err error
+
+		document, err = applyPatchOperation(document, opName, path, operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %s %s: %w", i, opName, path, err)
+		}
+	}
+
+	return document, nil
+}
+
+//nolint:cyclop // it's ok, switch by operation name
+func applyPatchOperation(document interface{},
+	opName, path string,
+	operation map[string]interface{},
+) (interface{}, error) {
+	value, hasValue := operation["value"]
+	from, isFromString := operation["from"].(string)
+
+	switch opName {
+	case patchOpAdd, patchOpReplace, patchOpTest:
+		if !hasValue {
+			return nil, fmt.Errorf("%w: value is required", ErrWrongJSONPatch)
+		}
+	case patchOpMove, patchOpCopy:
+		if !isFromString {
+			return nil, fmt.Errorf("%w: from must be a string", ErrWrongJSONPatch)
+		}
+
+		if opName == patchOpMove && strings.HasPrefix(path, from+"/") {
+			// location can't be moved into one of its children - RFC 6902, section 4.4
+			return nil, fmt.Errorf("%w: from must not be a proper prefix of path", ErrWrongJSONPatch)
+		}
+	case patchOpRemove:
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrWrongJSONPatch, opName)
+	}
+
+	switch opName {
+	case patchOpAdd:
+		return addByPointer(document, path, deepCopy(value))
+	case patchOpRemove:
+		result, _, err := removeByPointer(document, path)
+
+		return result, err
+	case patchOpReplace:
+		result, _, err := removeByPointer(document, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return addByPointer(result, path, deepCopy(value))
+	case patchOpMove:
+		result, movedValue, err := removeByPointer(document, from)
+		if err != nil {
+			return nil, err
+		}
+
+		return addByPointer(result, path, movedValue)
+	case patchOpCopy:
+		copiedValue, err := getByPointer(document, from)
+		if err != nil {
+			return nil, err
+		}
+
+		return addByPointer(document, path, deepCopy(copiedValue))
+	default: // test
+		actualValue, err := getByPointer(document, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isJSONEqual(actualValue, value) {
+			return nil, ErrJSONPatchTestFailed
+		}
+
+		return document, nil
+	}
+}
+
+func getByPointer(document interface{}, pointer string) (interface{}, error) {
+	elementsList, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	current := document
+
+	for _, element := range elementsList {
+		switch castedCurrent := current.(type) {
+		case map[string]interface{}:
+			value, isExists := castedCurrent[element]
+			if !isExists {
+				return nil, ErrJSONPointerIsNotFound
+			}
+
+			current = value
+		case []interface{}:
+			index, indexErr := parseArrayIndex(element, len(castedCurrent)-1)
+			if indexErr != nil {
+				return nil, indexErr
+			}
+
+			current = castedCurrent[index]
+		default:
+			return nil, ErrJSONPointerIsNotFound
+		}
+	}
+
+	return current, nil
+}
+
+// addByPointer - add value to object or insert value to array. Returns document, root of document
+// will be replaced for empty pointer...
+func addByPointer(document interface{}, pointer string, value interface{}) (interface{}, error) {
+	parent, lastElement, err := resolveParent(document, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if pointer == "" {
+		return value, nil
+	}
+
+	switch castedParent := parent.(type) {
+	case map[string]interface{}:
+		castedParent[lastElement] = value
+
+		return document, nil
+	case []interface{}:
+		index := len(castedParent)
+		if lastElement != appendIndex {
+			index, err = parseArrayIndex(lastElement, len(castedParent))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		castedParent = append(castedParent, nil)
+		copy(castedParent[index+1:], castedParent[index:])
+		castedParent[index] = value
+
+		return replaceArray(document, pointer, castedParent)
+	default:
+		return nil, ErrJSONPointerIsNotFound
+	}
+}
+
+// removeByPointer - remove value from document, returns document and removed value...
+func removeByPointer(document interface{}, pointer string) (interface{}, interface{}, error) {
+	removedValue, err := getByPointer(document, pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if pointer == "" {
+		return nil, removedValue, nil
+	}
+
+	parent, lastElement, err := resolveParent(document, pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch castedParent := parent.(type) {
+	case map[string]interface{}:
+		delete(castedParent, lastElement)
+
+		return document, removedValue, nil
+	case []interface{}:
+		index, _ := parseArrayIndex(lastElement, len(castedParent)-1)
+		castedParent = append(castedParent[:index], castedParent[index+1:]...)
+
+		result, err := replaceArray(document, pointer, castedParent)
+
+		return result, removedValue, err
+	default:
+		return nil, nil, ErrJSONPointerIsNotFound
+	}
+}
+
+// replaceArray - set changed array to parent of pointer target, arrays are values and
+// can't be changed in place on resize...
+func replaceArray(document interface{}, pointer string, array []interface{}) (interface{}, error) {
+	arrayPointer := pointer[:strings.LastIndex(pointer, "/")]
+	if arrayPointer == "" {
+		return array, nil
+	}
+
+	parent, lastElement, err := resolveParent(document, arrayPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	switch castedParent := parent.(type) {
+	case map[string]interface{}:
+		castedParent[lastElement] = array
+	case []interface{}:
+		index, _ := parseArrayIndex(lastElement, len(castedParent)-1)
+		castedParent[index] = array
+	}
+
+	return document, nil
+}
+
+// resolveParent returns parent value of pointer target and last element of pointer...
+func resolveParent(document interface{}, pointer string) (interface{}, string, error) {
+	if pointer == "" {
+		return nil, "", nil
+	}
+
+	separatorIndex := strings.LastIndex(pointer, "/")
+	if separatorIndex < 0 {
+		return nil, "", fmt.Errorf("%w: %s", ErrWrongJSONPointerElement, pointer)
+	}
+
+	parent, err := getByPointer(document, pointer[:separatorIndex])
+	if err != nil {
+		return nil, "", err
+	}
+
+	elementsList, err := parseJSONPointer(pointer[separatorIndex:])
+	if err != nil {
+		return nil, "", err
+	}
+
+	return parent, elementsList[0], nil
+}
+
+// parseJSONPointer - split JSON pointer (RFC 6901) to unescaped elements...
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: pointer must start with /: %s", ErrWrongJSONPointerElement, pointer)
+	}
+
+	elementsList := strings.Split(pointer[1:], "/")
+	for i, element := range elementsList {
+		elementsList[i] = strings.ReplaceAll(strings.ReplaceAll(element, "~1", "/"), "~0", "~")
+	}
+
+	return elementsList, nil
+}
+
+func parseArrayIndex(element string, maxIndex int) (int, error) {
+	index, err := strconv.Atoi(element)
+	if err != nil || index < 0 || index > maxIndex || (len(element) > 1 && element[0] == '0') {
+		return 0, fmt.Errorf("%w: array index %s", ErrWrongJSONPointerElement, element)
+	}
+
+	return index, nil
+}
+
+// isJSONEqual - compare JSON values, numbers are compared by value...
+func isJSONEqual(left, right interface{}) bool {
+	leftNumber, isLeftNumber := left.(json.Number)
+	rightNumber, isRightNumber := right.(json.Number)
+
+	if isLeftNumber && isRightNumber {
+		leftValue, leftErr := leftNumber.Float64()
+		rightValue, rightErr := rightNumber.Float64()
+
+		return leftErr == nil && rightErr == nil && leftValue == rightValue
+	}
+
+	leftObject, isLeftObject := left.(map[string]interface{})
+	rightObject, isRightObject := right.(map[string]interface{})
+
+	if isLeftObject && isRightObject {
+		if len(leftObject) != len(rightObject) {
+			return false
+		}
+
+		for key, leftValue := range leftObject {
+			rightValue, isExists := rightObject[key]
+			if !isExists || !isJSONEqual(leftValue, rightValue) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	leftArray, isLeftArray := left.([]interface{})
+	rightArray, isRightArray := right.([]interface{})
+
+	if isLeftArray && isRightArray {
+		if len(leftArray) != len(rightArray) {
+			return false
+		}
+
+		for i := range leftArray {
+			if !isJSONEqual(leftArray[i], rightArray[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch castedValue := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(castedValue))
+		for key, item := range castedValue {
+			result[key] = deepCopy(item)
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(castedValue))
+		for i, item := range castedValue {
+			result[i] = deepCopy(item)
+		}
+
+		return result
+	default:
+		return value
+	}
+}
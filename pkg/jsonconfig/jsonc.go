@@ -0,0 +1,127 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+var ErrUnterminatedComment = errors.New("unterminated block comment")
+
+// jsoncFileExtension - files with this extension are always processed in JSONC mode...
+const jsoncFileExtension = ".jsonc"
+
+// stripJSONC - convert JSON with comments and trailing commas to plain JSON. Comments and trailing commas
+// are replaced by spaces, line breaks are kept - so byte offsets, lines and columns of decoded document
+// are equal to positions in source document...
+//
+//nolint:gocognit,cyclop // it's ok, state machine of JSONC tokenizer
+func stripJSONC(data []byte) ([]byte, error) {
+	result := make([]byte, len(data))
+	copy(result, data)
+
+	pendingCommaIndex := -1
+
+	for i := 0; i < len(result); i++ {
+		symbol := result[i]
+
+		switch {
+		case symbol == '"':
+			pendingCommaIndex = -1
+			i = skipJSONString(result, i)
+		case symbol == '/' && i+1 < len(result) && result[i+1] == '/':
+			for ; i < len(result) && result[i] != '\n'; i++ {
+				result[i] = ' '
+			}
+		case symbol == '/' && i+1 < len(result) && result[i+1] == '*':
+			commentStart := i
+			isTerminated := false
+
+			for ; i < len(result); i++ {
+				if result[i] == '*' && i+1 < len(result) && result[i+1] == '/' {
+					result[i], result[i+1] = ' ', ' '
+					i++
+					isTerminated = true
+
+					break
+				}
+
+				blankNonLineBreak(result, i)
+			}
+
+			if !isTerminated {
+				return nil, newDecodeError(ErrUnterminatedComment, data, commentStart)
+			}
+		case symbol == ',':
+			pendingCommaIndex = i
+		case symbol == '}' || symbol == ']':
+			if pendingCommaIndex >= 0 {
+				result[pendingCommaIndex] = ' '
+			}
+
+			pendingCommaIndex = -1
+		case symbol == ' ' || symbol == '\t' || symbol == '\n' || symbol == '\r':
+			continue
+		default:
+			pendingCommaIndex = -1
+		}
+	}
+
+	return result, nil
+}
+
+// skipJSONString returns index of closing quote of JSON string, which starts at passed index...
+func skipJSONString(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return len(data)
+}
+
+func blankNonLineBreak(data []byte, index int) {
+	if data[index] != '\n' && data[index] != '\r' {
+		data[index] = ' '
+	}
+}
+
+func isJSONCFile(filePath string) bool {
+	return strings.EqualFold(filepath.Ext(trimGzipExtension(filePath)), jsoncFileExtension)
+}
@@ -0,0 +1,138 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStripJSONC(t *testing.T) {
+	rawData := []byte(`{
+	// database settings
+	"string_field": "http://example.com/*not a comment*/", /* inline
+	block comment */
+	"int_field_one": 1,
+	"list": [1, 2, 3,],
+}`)
+
+	strippedData, err := stripJSONC(rawData)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if len(strippedData) != len(rawData) {
+		t.Errorf("length of document must be kept: %d != %d", len(strippedData), len(rawData))
+	}
+
+	for i := range rawData {
+		if (rawData[i] == '\n') != (strippedData[i] == '\n') {
+			t.Errorf("line breaks must be kept: offset %d", i)
+			return
+		}
+	}
+
+	decodedData := make(map[string]interface{})
+
+	err = json.Unmarshal(strippedData, &decodedData)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if decodedData["string_field"] != "http://example.com/*not a comment*/" || len(decodedData) != 3 {
+		t.Errorf("not equal decoded document: %v", decodedData)
+	}
+
+	_, err = stripJSONC([]byte("{\n  \"key\": 1 /* comment\n}"))
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || !errors.Is(err, ErrUnterminatedComment) {
+		t.Errorf("expected decode error of unterminated comment: %v", err)
+		return
+	}
+
+	if decodeErr.Line != 2 || decodeErr.Column != 12 {
+		t.Errorf("not equal position of error: %d:%d", decodeErr.Line, decodeErr.Column)
+	}
+}
+
+func TestServiceJSONC(t *testing.T) {
+	rawData := []byte(`{
+	// comment
+	"int_field_one": 1, /* port of database */ "db_port": "5432",
+}`)
+
+	unmarshaledData := &SimpleJSONCase{}
+
+	err := NewService(nil).WithJSONC().PrepareTo(unmarshaledData).PrepareFrom(rawData).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if unmarshaledData.IntFieldOne != 1 || unmarshaledData.GetPort() != 5432 {
+		t.Errorf("not equal unmarshaled data")
+	}
+
+	err = NewService(nil).PrepareTo(&SimpleJSONCase{}).PrepareFrom(rawData).Do(context.Background())
+	if err == nil {
+		t.Errorf("comments must be rejected without JSONC mode")
+	}
+
+	filePath := filepath.Join(t.TempDir(), "config.jsonc")
+
+	err = os.WriteFile(filePath, []byte("{\n  /* комментарий */ \"int_field_one\": \"one\"\n}"), 0o600)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	err = NewService(nil).PrepareTo(&SimpleJSONCase{}).PrepareFromFile(filePath).Do(context.Background())
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("expected decode error: %v", err)
+		return
+	}
+
+	// columns counted in characters of source document
+	if decodeErr.File != filePath || decodeErr.Line != 2 || decodeErr.Column != 38 {
+		t.Errorf("not equal position of error: %s", decodeErr)
+	}
+}
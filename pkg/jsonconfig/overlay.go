@@ -0,0 +1,251 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+var ErrWrongOverlay = errors.New("overlay must be a JSON object or an array of JSON patch operations")
+
+const overlaySourceName = "overlay"
+
+// documentOverlayer - applier of overlay files to base document. Overlay format detected by root value:
+//   - object - JSON Merge Patch (RFC 7396), null values remove keys of base document
+//   - array - JSON Patch (RFC 6902) operations list
+type documentOverlayer struct {
+	logger *common.Logger
+	parser *documentComposer
+}
+
+// Apply - apply overlay files to document of file in order of list, returns patched document and origins
+// of its values. Origins of document values are optional - values of document are treated as values of file...
+func (o *documentOverlayer) Apply(data []byte,
+	filePath string,
+	origins documentOrigins,
+	overlayPathsList []string,
+) ([]byte, documentOrigins, error) {
+	document, fileOrigins, err := o.parser.parse(data, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if origins == nil {
+		origins = fileOrigins
+	}
+
+	for _, overlayPath := range overlayPathsList {
+		rawOverlay, readErr := o.parser.files.ReadFile(overlayPath)
+		if readErr != nil {
+			return nil, nil, readErr
+		}
+
+		o.logger.SourceDiscovered(overlaySourceName+":"+overlayPath, 0)
+
+		overlayDocument, overlayOrigins, parseErr := o.parser.parse(rawOverlay, overlayPath)
+		if parseErr != nil {
+			return nil, nil, parseErr
+		}
+
+		document, err = applyOverlay(document, overlayDocument, &originsPatcher{
+			origins:        origins,
+			overlayOrigins: overlayOrigins,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", overlayPath, err)
+		}
+	}
+
+	overlaidData, err := json.Marshal(document)
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck // it's ok, json error
+	}
+
+	return overlaidData, origins, nil
+}
+
+// applyOverlay - apply overlay document to document. Origins patcher is optional...
+func applyOverlay(document, overlayDocument interface{}, originsPatcherSvc *originsPatcher) (interface{}, error) {
+	switch castedOverlay := overlayDocument.(type) {
+	case map[string]interface{}:
+		if originsPatcherSvc != nil {
+			originsPatcherSvc.MergePatch()
+		}
+
+		return applyMergePatch(document, castedOverlay), nil
+	case []interface{}:
+		return applyJSONPatch(document, castedOverlay, originsPatcherSvc)
+	default:
+		return nil, ErrWrongOverlay
+	}
+}
+
+// originsPatcher - updater of document origins by values of overlay. Origins of array items are shifted
+// on insertion or removal of items...
+type originsPatcher struct {
+	origins        documentOrigins
+	overlayOrigins documentOrigins
+}
+
+// MergePatch - update origins by JSON Merge Patch overlay...
+func (p *originsPatcher) MergePatch() {
+	p.origins.Merge(p.overlayOrigins)
+}
+
+// PatchOperation - update origins by JSON Patch operation, must be called before applying of operation...
+func (p *originsPatcher) PatchOperation(document interface{}, index int, opName, path, from string) {
+	valueOrigins := p.overlayOrigins.Subtree("/" + strconv.Itoa(index) + "/value")
+
+	switch opName {
+	case patchOpAdd:
+		p.add(document, path, valueOrigins)
+	case patchOpReplace:
+		p.origins.Remove(path)
+		p.origins.Merge(valueOrigins.Rebase(path))
+	case patchOpRemove:
+		p.remove(document, path)
+	case patchOpMove:
+		movedOrigins := p.origins.Subtree(from)
+
+		p.remove(document, from)
+		p.add(document, path, movedOrigins)
+	case patchOpCopy:
+		p.add(document, path, p.origins.Subtree(from))
+	default:
+		return
+	}
+}
+
+func (p *originsPatcher) add(document interface{}, pointer string, valueOrigins documentOrigins) {
+	arrayPointer, index, isArrayItem := parentArray(document, pointer)
+	if isArrayItem {
+		p.origins.ShiftItems(arrayPointer, index, 1)
+		pointer = arrayPointer + "/" + strconv.Itoa(index)
+	}
+
+	p.origins.Remove(pointer)
+	p.origins.Merge(valueOrigins.Rebase(pointer))
+}
+
+func (p *originsPatcher) remove(document interface{}, pointer string) {
+	p.origins.Remove(pointer)
+
+	arrayPointer, index, isArrayItem := parentArray(document, pointer)
+	if isArrayItem {
+		p.origins.ShiftItems(arrayPointer, index+1, -1)
+	}
+}
+
+// parentArray returns pointer of parent array and index of item, if value by pointer is an item of array.
+// Index of appended item is equal to length of array...
+func parentArray(document interface{}, pointer string) (string, int, bool) {
+	separatorIndex := strings.LastIndex(pointer, "/")
+	if separatorIndex < 0 {
+		return "", 0, false
+	}
+
+	parent, err := getByPointer(document, pointer[:separatorIndex])
+	if err != nil {
+		return "", 0, false
+	}
+
+	array, isArray := parent.([]interface{})
+	if !isArray {
+		return "", 0, false
+	}
+
+	element := pointer[separatorIndex+1:]
+	if element == appendIndex {
+		return pointer[:separatorIndex], len(array), true
+	}
+
+	index, err := strconv.Atoi(element)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return pointer[:separatorIndex], index, true
+}
+
+// environmentOverlayPath - path of environment overlay of config file: config.json -> config.production.json.
+// Extension .gz is kept: config.json.gz -> config.production.json.gz. Returns empty string if overlay file doesn't exist...
+func environmentOverlayPath(files *sourceFilesReader, filePath, environmentName string) string {
+	if filePath == "" || environmentName == "" {
+		return ""
+	}
+
+	uncompressedPath := trimGzipExtension(filePath)
+	extension := filepath.Ext(uncompressedPath)
+	overlayPath := strings.TrimSuffix(uncompressedPath, extension) + "." + environmentName + extension +
+		filePath[len(uncompressedPath):]
+
+	if !files.IsFileExists(overlayPath) {
+		return ""
+	}
+
+	return overlayPath
+}
+
+// indentDocument - format document for printing. Document returned as is, if it's not a valid JSON...
+func indentDocument(data []byte) []byte {
+	var buffer bytes.Buffer
+
+	err := json.Indent(&buffer, data, "", "  ")
+	if err != nil {
+		return append([]byte(nil), data...)
+	}
+
+	return buffer.Bytes()
+}
+
+func newDocumentOverlayer(logger *common.Logger, files *sourceFilesReader, isJSONC bool) *documentOverlayer {
+	return &documentOverlayer{
+		logger: logger,
+		parser: &documentComposer{
+			logger:      logger,
+			files:       files,
+			rootDir:     "",
+			filesStack:  nil,
+			loadedCount: 0,
+			isJSONC:     isJSONC,
+		},
+	}
+}
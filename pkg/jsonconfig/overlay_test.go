@@ -0,0 +1,320 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/config"
+)
+
+type environmentNameProvider struct {
+	environmentName string
+}
+
+func (p *environmentNameProvider) GetEnvironmentName() string {
+	return p.environmentName
+}
+
+type environmentProvider struct {
+	environmentNameProvider
+
+	environment *config.EnvironmentDefinition
+}
+
+func (p *environmentProvider) GetEnvironment() *config.EnvironmentDefinition {
+	return p.environment
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	testCases := []struct {
+		expectedErr error
+		name        string
+		document    string
+		patch       string
+		expected    string
+	}{
+		{
+			expectedErr: nil,
+			name:        "add, replace and remove",
+			document:    `{"a": 1, "b": {"c": 2}, "d": 3}`,
+			patch: `[{"op": "add", "path": "/b/e", "value": [1]}, {"op": "replace", "path": "/a", "value": "x"},
+				{"op": "remove", "path": "/d"}]`,
+			expected: `{"a":"x","b":{"c":2,"e":[1]}}`,
+		},
+		{
+			expectedErr: nil,
+			name:        "array insert and append",
+			document:    `{"list": [1, 3]}`,
+			patch:       `[{"op": "add", "path": "/list/1", "value": 2}, {"op": "add", "path": "/list/-", "value": 4}]`,
+			expected:    `{"list":[1,2,3,4]}`,
+		},
+		{
+			expectedErr: nil,
+			name:        "move, copy and escaped pointer",
+			document:    `{"a/b": {"x": 1}, "list": [5, 6]}`,
+			patch: `[{"op": "move", "from": "/a~1b", "path": "/c"}, {"op": "copy", "from": "/list/0", "path": "/d"},
+				{"op": "remove", "path": "/list/0"}, {"op": "test", "path": "/c/x", "value": 1.0}]`,
+			expected: `{"c":{"x":1},"d":5,"list":[6]}`,
+		},
+		{
+			expectedErr: ErrJSONPatchTestFailed,
+			name:        "failed test",
+			document:    `{"a": 1}`,
+			patch:       `[{"op": "test", "path": "/a", "value": 2}]`,
+			expected:    "",
+		},
+		{
+			expectedErr: ErrJSONPointerIsNotFound,
+			name:        "missing path",
+			document:    `{"a": 1}`,
+			patch:       `[{"op": "replace", "path": "/b", "value": 2}]`,
+			expected:    "",
+		},
+		{
+			expectedErr: ErrWrongJSONPatch,
+			name:        "move to child of from location",
+			document:    `{"a": {"b": 1}}`,
+			patch:       `[{"op": "move", "from": "/a", "path": "/a/c"}]`,
+			expected:    "",
+		},
+		{
+			expectedErr: nil,
+			name:        "move to sibling with same prefix",
+			document:    `{"a": 1}`,
+			patch:       `[{"op": "move", "from": "/a", "path": "/ab"}]`,
+			expected:    `{"ab":1}`,
+		},
+		{
+			expectedErr: ErrWrongJSONPatch,
+			name:        "unknown operation",
+			document:    `{"a": 1}`,
+			patch:       `[{"op": "merge", "path": "/a", "value": 2}]`,
+			expected:    "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		overlayer := newDocumentOverlayer(nil, newSourceFilesReader(nil, 0), false)
+
+		document, _, err := overlayer.parser.parse([]byte(testCase.document), "")
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.name, err)
+		}
+
+		patch, _, err := overlayer.parser.parse([]byte(testCase.patch), "")
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.name, err)
+		}
+
+		result, err := applyOverlay(document, patch, nil)
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Errorf("%s: not equal error: %v", testCase.name, err)
+
+			continue
+		}
+
+		if testCase.expectedErr != nil {
+			continue
+		}
+
+		resultData, _ := json.Marshal(result)
+		if string(resultData) != testCase.expected {
+			t.Errorf("%s: not equal result: %s", testCase.name, resultData)
+		}
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	var document, patch interface{}
+
+	_ = json.Unmarshal([]byte(`{"a": "b", "c": {"d": "e", "f": "g"}, "list": [1, 2]}`), &document)
+	_ = json.Unmarshal([]byte(`{"a": "z", "c": {"f": null}, "list": [3], "new": {"x": 1}}`), &patch)
+
+	resultData, _ := json.Marshal(applyMergePatch(document, patch))
+	if string(resultData) != `{"a":"z","c":{"d":"e"},"list":[3],"new":{"x":1}}` {
+		t.Errorf("not equal result: %s", resultData)
+	}
+}
+
+func TestServiceEnvironmentOverlays(t *testing.T) {
+	rootDir := t.TempDir()
+
+	writeTestFiles(t, rootDir, map[string]string{
+		"config.json": `{"top_level_field_int": 1, "list": [{"db_port": "1", "string_field": "base"}]}`,
+		"config.production.json": `{
+			// production values
+			"top_level_field_int": 2,
+			"list": [{"db_port": "2", "db_user": "!secret:USER"}],
+		}`,
+		"patch.json": `[{"op": "add", "path": "/list/-", "value": {"db_port": "3"}}]`,
+	})
+
+	cfg := &MixedJSONCase{}
+	svc := NewService(nil).PrepareTo(cfg).PrepareFromFile(filepath.Join(rootDir, "config.json")).
+		WithJSONC().WithOverlays(filepath.Join(rootDir, "patch.json"))
+
+	err := svc.With(&environmentNameProvider{environmentName: "production"},
+		&mockSecretManager{ValuesPool: map[string]string{"USER": "user"}}).
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.TopLevelField != 2 || len(cfg.List) != 2 || cfg.List[0].GetPort() != 2 ||
+		cfg.List[0].StringField != "" || cfg.List[0].DBUser != "user" || cfg.List[1].GetPort() != 3 {
+		t.Errorf("not equal overlaid config: %+v", cfg)
+	}
+
+	mergedDocument := string(svc.MergedDocument())
+	if !strings.Contains(mergedDocument, `"db_user": "!secret:USER"`) ||
+		!strings.Contains(mergedDocument, "\n  \"list\": [") {
+		t.Errorf("not equal merged document: %s", mergedDocument)
+	}
+
+	cfg = &MixedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).PrepareFromFile(filepath.Join(rootDir, "config.json")).
+		WithEnvironment("staging").Do(context.Background())
+	if err != nil || cfg.TopLevelField != 1 {
+		t.Errorf("base config expected without environment overlay: %v", err)
+	}
+}
+
+func TestServiceCanonicalEnvironmentOverlay(t *testing.T) {
+	rootDir := t.TempDir()
+
+	writeTestFiles(t, rootDir, map[string]string{
+		"config.json":            `{"top_level_field_int": 1}`,
+		"config.production.json": `{"top_level_field_int": 2}`,
+		"config.prod.json":       `{"top_level_field_int": 3}`,
+	})
+
+	configPath := filepath.Join(rootDir, "config.json")
+
+	cfg := &MixedJSONCase{}
+
+	err := NewService(nil).PrepareTo(cfg).PrepareFromFile(configPath).With(&environmentProvider{
+		environmentNameProvider: environmentNameProvider{environmentName: "prod"},
+		environment:             &config.EnvironmentDefinition{Name: "production", Aliases: []string{"prod"}},
+	}).Do(context.Background())
+	if err != nil || cfg.TopLevelField != 2 {
+		t.Errorf("overlay of canonical environment name expected: %d, %v", cfg.TopLevelField, err)
+	}
+
+	cfg = &MixedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).PrepareFromFile(configPath).WithEnvironment("prod").
+		With(config.NewDefaultEnvironmentRegistry()).Do(context.Background())
+	if err != nil || cfg.TopLevelField != 2 {
+		t.Errorf("alias must be replaced by canonical environment name: %d, %v", cfg.TopLevelField, err)
+	}
+}
+
+func TestServiceOverlayErrorPositions(t *testing.T) {
+	rootDir := t.TempDir()
+
+	writeTestFiles(t, rootDir, map[string]string{
+		"config.json": `{
+			"top_level_field_int": "1",
+			"list": [{"db_port": "1"}]
+		}`,
+		"merge.json": `{"list": [
+			{"string_field": 2}
+		]}`,
+		"patch.json": `[
+			{"op": "add", "path": "/list/0", "value": {"int_field_one": "x"}}
+		]`,
+	})
+
+	err := NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFromFile(filepath.Join(rootDir, "config.json")).
+		WithOverlays(filepath.Join(rootDir, "merge.json"), filepath.Join(rootDir, "patch.json")).
+		Do(context.Background())
+
+	expectedErrorsList := []struct {
+		file    string
+		pointer string
+		line    int
+		column  int
+	}{
+		{"config.json", "/top_level_field_int", 2, 27},
+		{"merge.json", "/list/1/string_field", 2, 21},
+		{"patch.json", "/list/0/int_field_one", 2, 64},
+	}
+
+	for _, expectedErr := range expectedErrorsList {
+		if !hasDecodeErrorAt(err, expectedErr.pointer, expectedErr.file, expectedErr.line, expectedErr.column) {
+			t.Errorf("expected decode error of %s in %s:%d:%d: %v",
+				expectedErr.pointer, expectedErr.file, expectedErr.line, expectedErr.column, err)
+		}
+	}
+}
+
+func TestServiceWrongOverlay(t *testing.T) {
+	rootDir := t.TempDir()
+
+	writeTestFiles(t, rootDir, map[string]string{
+		"config.json":     `{"top_level_field_int": 1}`,
+		"scalar.json":     `"value"`,
+		"wrong_test.json": `[{"op": "test", "path": "/top_level_field_int", "value": 5}]`,
+		"broken.json":     "{\n  \"top_level_field_int\": ,\n}",
+	})
+
+	configPath := filepath.Join(rootDir, "config.json")
+
+	err := NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFromFile(configPath).
+		WithOverlays(filepath.Join(rootDir, "scalar.json")).Do(context.Background())
+	if !errors.Is(err, ErrWrongOverlay) {
+		t.Errorf("expected wrong overlay error: %v", err)
+	}
+
+	err = NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFromFile(configPath).
+		WithOverlays(filepath.Join(rootDir, "wrong_test.json")).Do(context.Background())
+	if !errors.Is(err, ErrJSONPatchTestFailed) {
+		t.Errorf("expected failed test operation error: %v", err)
+	}
+
+	err = NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFromFile(configPath).
+		WithOverlays(filepath.Join(rootDir, "broken.json")).Do(context.Background())
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Line != 2 || !strings.HasSuffix(decodeErr.File, "broken.json") {
+		t.Errorf("expected positioned decode error of overlay file: %v", err)
+	}
+}
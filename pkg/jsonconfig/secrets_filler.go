@@ -33,12 +33,24 @@
 package jsonconfig
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
+)
+
+const (
+	secretSourceName = "secret"
+	// secretValuePrefix - prefix of string values with secret reference in format
+	// [scheme:]path[#field][@version][|default]...
+	secretValuePrefix = secrets.ReferencePrefix
 )
 
 var (
@@ -50,125 +62,300 @@ var (
 
 type secretFiller struct {
 	e              errorFormatterService
-	secretsDataSvc secretManagerService
+	secretsDataSvc secretProviderService
+
+	target             interface{}
+	logger             *common.Logger
+	resolvedSecretsMap map[string]secrets.Secret
+	dependenciesSvc    []interface{}
+}
+
+func (u *secretFiller) Process(ctx context.Context) error {
+	targetValue := reflect.ValueOf(u.target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return u.e.ErrorOnly(ErrPassedStructMustBeAPointer)
+	}
+
+	if targetValue.Elem().Kind() != reflect.Struct {
+		return u.e.ErrorOnly(ErrPassedStructMustBeAStructPointer)
+	}
+
+	rootPath := targetValue.Elem().Type().Name()
 
-	target          interface{}
-	dependenciesSvc []interface{}
+	refsList, err := u.collectSecretFields(ctx, targetValue, rootPath)
+	if err != nil {
+		return u.e.ErrorNoWrap(err)
+	}
+
+	err = u.resolveSecrets(ctx, refsList)
+	if err != nil {
+		return u.e.ErrorNoWrap(err)
+	}
+
+	return u.walkValue(ctx, targetValue, rootPath, reflect.StructField{}, false, &secretWalkHandlers{
+		onSecret:    u.fillSecret,
+		onStructEnd: u.prepareStruct,
+	})
 }
 
-func (u *secretFiller) Process() error {
-	return u.processFields(u.target)
+// secretWalkHandlers - handlers of walkValue function:
+//   - onSecret called for each string value with secret tag, including items of slices, arrays and maps
+//   - onStructEnd called for each struct after processing of all struct fields
+type secretWalkHandlers struct {
+	onSecret    func(value reflect.Value, path string, structField reflect.StructField) error
+	onStructEnd func(element reflect.Value, path string) error
 }
 
-// extractFields returns information of the struct fields, including nested structures
-// based on https://github.com/kelseyhightower/envconfig
-// TODO: refactor it - separate by sub-function
+// walkValue - traverse value of config recursively: structs, pointers, slices, arrays and maps.
+// Secret tag of struct field applied to all strings of field value - to items of []string, map[string]string,
+// *string and nested slices, but not to fields of nested structs - they have own tags.
+// Values of maps aren't addressable, so they are processed as copies, which are set back to map...
 //
-//nolint:funlen,gocognit,cyclop // it's ok. Need to refactor this function, but now - it's ok.
-func (u *secretFiller) processFields(target interface{}) error {
-	targetSource := reflect.ValueOf(target)
-
-	// must be a pointer
-	switch targetSource.Kind() {
-	case reflect.Slice:
-	case reflect.Ptr:
+//nolint:cyclop // it's ok, switch by value kind
+func (u *secretFiller) walkValue(ctx context.Context,
+	value reflect.Value,
+	path string,
+	structField reflect.StructField,
+	isSecret bool,
+	handlers *secretWalkHandlers,
+) error {
+	err := ctx.Err()
+	if err != nil {
+		return u.e.ErrorOnly(err, path)
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+
+		return u.walkValue(ctx, value.Elem(), path, structField, isSecret, handlers)
+	case reflect.Struct:
+		return u.walkStruct(ctx, value, path, handlers)
+	case reflect.Slice, reflect.Array:
+		for j := range value.Len() {
+			walkErr := u.walkValue(ctx, value.Index(j), path+"["+strconv.Itoa(j)+"]", structField, isSecret, handlers)
+			if walkErr != nil {
+				return walkErr
+			}
+		}
+
+		return nil
+	case reflect.Map:
+		return u.walkMap(ctx, value, path, structField, isSecret, handlers)
+	case reflect.String:
+		if !isSecret || !value.CanSet() || !strings.HasPrefix(value.String(), secretValuePrefix) {
+			return nil
+		}
+
+		return handlers.onSecret(value, path, structField)
 	default:
-		return u.e.ErrorOnly(ErrPassedStructMustBeAPointer)
+		return nil
 	}
+}
 
-	// pointer must refer to structure
-	element := targetSource.Elem()
+func (u *secretFiller) walkStruct(ctx context.Context,
+	element reflect.Value,
+	path string,
+	handlers *secretWalkHandlers,
+) error {
 	elemType := element.Type()
 
-	// iterate over struct fields
-	numFields := elemType.NumField()
-	for i := range numFields {
-		structField := elemType.Field(i) // struct field info
+	for i := range elemType.NumField() {
+		structField := elemType.Field(i)
+		fieldPath := path + "." + structField.Name
 
-		fieldValue := element.Field(i) // reflect.RfValue
+		fieldValue := element.Field(i)
 		if !fieldValue.CanSet() {
 			continue
 		}
 
-		// recursively process nested struct
-		if fieldValue.Kind() == reflect.Struct && fieldValue.CanInterface() {
-			processErr := u.processFields(fieldValue.Addr().Interface())
-			if processErr != nil {
-				return u.e.ErrorOnly(processErr)
+		isSecret := false
+
+		boolVarSrt, isTagExists := structField.Tag.Lookup(common.TagSecret)
+		if isTagExists {
+			boolVar, err := strconv.ParseBool(boolVarSrt)
+			if err != nil {
+				return u.e.ErrorNoWrap(common.NewFieldError(
+					&common.ParseError{Cause: err, Value: boolVarSrt, Type: "bool"},
+					fieldPath, common.Field{
+						Name:      structField.Name,
+						EnvKey:    "",
+						SecretKey: "",
+						Source:    "",
+						RfValue:   fieldValue,
+						RfTags:    structField.Tag,
+						Value:     "",
+					}, false))
 			}
 
-			continue
+			isSecret = boolVar
 		}
 
-		if fieldValue.Kind() == reflect.Slice && fieldValue.CanInterface() {
-			for j := range fieldValue.Len() {
-				item := fieldValue.Index(j)
+		err := u.walkValue(ctx, fieldValue, fieldPath, structField, isSecret, handlers)
+		if err != nil {
+			return err
+		}
+	}
 
-				indirectValue := reflect.Indirect(item)
-				if indirectValue.Kind() == reflect.Struct {
-					processErr := u.processFields(indirectValue.Addr().Interface())
-					if processErr != nil {
-						return u.e.ErrorOnly(processErr)
-					}
+	if handlers.onStructEnd == nil || !element.CanAddr() {
+		return nil
+	}
 
-					continue
-				}
-			}
-		}
+	return handlers.onStructEnd(element, path)
+}
 
-		boolVarSrt, isTagExists := structField.Tag.Lookup(common.TagSecret)
-		if !isTagExists {
-			continue
-		}
+func (u *secretFiller) walkMap(ctx context.Context,
+	mapValue reflect.Value,
+	path string,
+	structField reflect.StructField,
+	isSecret bool,
+	handlers *secretWalkHandlers,
+) error {
+	keysList := mapValue.MapKeys()
+	sort.Slice(keysList, func(i, j int) bool {
+		return fmt.Sprint(keysList[i].Interface()) < fmt.Sprint(keysList[j].Interface())
+	})
+
+	for _, key := range keysList {
+		itemCopy := reflect.New(mapValue.Type().Elem()).Elem()
+		itemCopy.Set(mapValue.MapIndex(key))
 
-		boolVar, err := strconv.ParseBool(boolVarSrt)
+		err := u.walkValue(ctx, itemCopy, path+"["+fmt.Sprint(key.Interface())+"]", structField, isSecret, handlers)
 		if err != nil {
-			return u.e.ErrorOnly(err)
+			return err
 		}
 
-		if !boolVar {
-			continue
-		}
+		mapValue.SetMapIndex(key, itemCopy)
+	}
 
-		value := fieldValue.String()
-		if !strings.HasPrefix(value, "!secret:") {
-			continue
-		}
+	return nil
+}
 
-		separated := strings.Split(value, ":")
-		if len(separated) > 2 {
-			return u.e.ErrorOnly(ErrWrongSecretStringFormat)
-		}
+// fillSecret - set resolved secret value to string with "!secret:" prefix...
+func (u *secretFiller) fillSecret(value reflect.Value, path string, structField reflect.StructField) error {
+	rawValue := value.String()
 
-		secretKey := separated[1]
+	reference, err := secrets.ParseReference(strings.TrimPrefix(rawValue, secretValuePrefix))
+	if err != nil {
+		return u.e.ErrorNoWrap(common.NewFieldError(&common.ValidationError{
+			Cause: fmt.Errorf("%w: %w", ErrWrongSecretStringFormat, err),
+			Rule:  common.ValidationRuleSecretFormat,
+		}, path, common.Field{
+			Name:      structField.Name,
+			EnvKey:    "",
+			SecretKey: "",
+			Source:    secretSourceName,
+			RfValue:   value,
+			RfTags:    structField.Tag,
+			Value:     rawValue,
+		}, false))
+	}
 
-		value, isExists := u.secretsDataSvc.GetByName(secretKey)
-		if !isExists {
-			return u.e.ErrorOnly(ErrVariableEmptyButRequired, structField.Name)
-		}
+	commonField := newSecretField(value, structField, reference.Key())
 
-		err = common.SetField(value, fieldValue)
-		if err != nil {
-			return u.e.ErrorOnly(err)
-		}
+	secret, isExists := reference.Lookup(u.resolvedSecretsMap)
+	u.logger.SecretFetched(path, commonField.EnvKey, u.secretsDataSvc, isExists)
+
+	if !isExists {
+		return u.e.ErrorNoWrap(common.NewFieldError(&common.ValidationError{
+			Cause: ErrVariableEmptyButRequired,
+			Rule:  common.ValidationRuleRequired,
+		}, path, commonField, true))
 	}
 
+	commonField.Value = secret.Value
+
+	err = common.SetField(secret.Value, value)
+	if err != nil {
+		return u.e.ErrorNoWrap(common.NewFieldError(err, path, commonField, true))
+	}
+
+	u.logger.FieldResolved(path, commonField.EnvKey, secretSourceName, secret.Value, true)
+
+	return nil
+}
+
+// prepareStruct - call PrepareWith and Prepare functions of nested config struct...
+func (u *secretFiller) prepareStruct(element reflect.Value, path string) error {
 	castedField, isPossibleToCast := element.Addr().Interface().(configService)
 	if !isPossibleToCast {
 		return nil
 	}
 
 	if u.dependenciesSvc != nil {
+		startedAt := time.Now()
 		prepErr := castedField.PrepareWith(u.dependenciesSvc...)
+		u.logger.HookFinished(path, "PrepareWith", time.Since(startedAt), prepErr)
+
 		if prepErr != nil {
 			return u.e.ErrorOnly(prepErr)
 		}
 	}
 
+	startedAt := time.Now()
 	prepErr := castedField.Prepare()
+	u.logger.HookFinished(path, "Prepare", time.Since(startedAt), prepErr)
+
 	if prepErr != nil {
 		return u.e.ErrorOnly(prepErr)
 	}
 
 	return nil
 }
+
+// collectSecretFields - discovery pass of unmarshalled config. Collects values with "!secret:" prefix
+// of all nested structs, pointers, slices and maps in order of traversal. Values in invalid format are skipped -
+// they will be reported by assignment pass...
+func (u *secretFiller) collectSecretFields(ctx context.Context,
+	target reflect.Value,
+	path string,
+) ([]secrets.FieldReference, error) {
+	refsList := make([]secrets.FieldReference, 0)
+
+	err := u.walkValue(ctx, target, path, reflect.StructField{}, false, &secretWalkHandlers{
+		onSecret: func(value reflect.Value, path string, structField reflect.StructField) error {
+			reference, err := secrets.ParseReference(strings.TrimPrefix(value.String(), secretValuePrefix))
+			if err != nil {
+				return nil
+			}
+
+			refsList = append(refsList, secrets.FieldReference{
+				Field:     newSecretField(value, structField, reference.Key()),
+				Reference: reference,
+				Path:      path,
+			})
+
+			return nil
+		},
+		onStructEnd: nil,
+	})
+
+	return refsList, err
+}
+
+func newSecretField(value reflect.Value, structField reflect.StructField, secretKey string) common.Field {
+	return common.Field{
+		Name:      structField.Name,
+		EnvKey:    secretKey,
+		SecretKey: secretKey,
+		Source:    secretSourceName,
+		RfValue:   value,
+		RfTags:    structField.Tag,
+		Value:     "",
+	}
+}
+
+// resolveSecrets - resolution pass. Fetches all discovered secrets before assignment of values.
+// Errors of all failed fields are aggregated in order of traversal...
+func (u *secretFiller) resolveSecrets(ctx context.Context, refsList []secrets.FieldReference) error {
+	resolvedSecretsMap, err := secrets.ResolveFieldReferences(ctx, u.secretsDataSvc, refsList)
+	if err != nil {
+		return u.e.ErrorNoWrap(err)
+	}
+
+	u.resolvedSecretsMap = resolvedSecretsMap
+
+	return nil
+}
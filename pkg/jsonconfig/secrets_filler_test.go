@@ -0,0 +1,126 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
+)
+
+func TestServiceNestedSecrets(t *testing.T) {
+	rawData := []byte(`{
+		"nodes": {"btc": {"password": "!secret:BTC_PASSWORD", "address": "!secret:NOT_SECRET"}},
+		"node_ptrs": {"eth": {"password": "!secret:ETH_PASSWORD"}},
+		"api_keys": ["!secret:API_KEY_1", "plain", "!secret:API_KEY_2"],
+		"tokens": {"first": "!secret:TOKEN"},
+		"shards": [["!secret:SHARD_1"], [], ["!secret:SHARD_2"]],
+		"tls": {"key_password": "!secret:TLS_PASSWORD"},
+		"public": ["!secret:PUBLIC"],
+		"wrong_type": [1]
+	}`)
+
+	valuesPool := map[string]string{
+		"BTC_PASSWORD": "btc", "ETH_PASSWORD": "eth", "API_KEY_1": "key1", "API_KEY_2": "key2",
+		"TOKEN": "token", "SHARD_1": "shard1", "SHARD_2": "shard2", "TLS_PASSWORD": "tls",
+	}
+
+	cfg := &nestedSecretsJSONCase{}
+
+	err := NewService(nil).PrepareTo(cfg).PrepareFrom(rawData).
+		With(&mockSecretManager{ValuesPool: valuesPool}).
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.NodesMap["btc"].Password != "btc" || cfg.NodesMap["btc"].Address != "!secret:NOT_SECRET" ||
+		cfg.NodesMap["btc"].preparedCount != 1 || cfg.NodePtrsMap["eth"].Password != "eth" {
+		t.Errorf("not equal secrets of maps: %+v, %+v", cfg.NodesMap, cfg.NodePtrsMap["eth"])
+	}
+
+	if cfg.APIKeysList[0] != "key1" || cfg.APIKeysList[1] != "plain" || cfg.APIKeysList[2] != "key2" ||
+		cfg.TokensMap["first"] != "token" || cfg.ShardsList[0][0] != "shard1" || cfg.ShardsList[2][0] != "shard2" {
+		t.Errorf("not equal secrets of slices: %v, %v, %v", cfg.APIKeysList, cfg.TokensMap, cfg.ShardsList)
+	}
+
+	if *cfg.TLS.KeyPassword != "tls" || cfg.PublicList[0] != "!secret:PUBLIC" {
+		t.Errorf("not equal secrets of pointers: %s, %v", *cfg.TLS.KeyPassword, cfg.PublicList)
+	}
+
+	err = NewService(nil).PrepareTo(&nestedSecretsJSONCase{}).
+		PrepareFrom([]byte(`{"shards": [["!secret:SHARD_1", "!secret:UNKNOWN"]]}`)).
+		With(&mockSecretManager{ValuesPool: valuesPool}).
+		Do(context.Background())
+
+	var fieldErr *common.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Path != "nestedSecretsJSONCase.ShardsList[0][1]" {
+		t.Errorf("expected field error of nested slice item: %v", err)
+	}
+}
+
+func TestServiceSecretReferences(t *testing.T) {
+	rawData := []byte(`{
+		"api_keys": ["!secret:vault:wallet/hot#key@v3", "!secret:vault:wallet/cold#key|fallback:value", "!secret:API_KEY"]
+	}`)
+
+	vaultProvider := secrets.AdaptSecretManager(&mockSecretManager{
+		ValuesPool: map[string]string{"wallet/hot#key@v3": "hot"},
+	})
+	router := secrets.NewSchemeRouter(secrets.AdaptSecretManager(&mockSecretManager{
+		ValuesPool: map[string]string{"API_KEY": "key"},
+	}), map[string]secrets.SecretProvider{"vault": vaultProvider})
+
+	cfg := &nestedSecretsJSONCase{}
+
+	err := NewService(nil).PrepareTo(cfg).PrepareFrom(rawData).With(router).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.APIKeysList[0] != "hot" || cfg.APIKeysList[1] != "fallback:value" || cfg.APIKeysList[2] != "key" {
+		t.Errorf("not equal secrets of references: %v", cfg.APIKeysList)
+	}
+
+	err = NewService(nil).PrepareTo(&nestedSecretsJSONCase{}).
+		PrepareFrom([]byte(`{"api_keys": ["!secret:vault:wallet/hot@"]}`)).
+		With(router).Do(context.Background())
+	if !errors.Is(err, ErrWrongSecretStringFormat) || !errors.Is(err, secrets.ErrWrongSecretReference) {
+		t.Errorf("expected wrong secret reference error: %v", err)
+	}
+}
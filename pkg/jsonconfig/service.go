@@ -33,37 +33,148 @@
 package jsonconfig
 
 import (
+	"bytes"
 	"context"
-	"os"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
 
 	"github.com/mailru/easyjson"
 	"github.com/mailru/easyjson/jlexer"
 )
 
+const (
+	fileSourceName   = "file"
+	fsSourceName     = "fs"
+	readerSourceName = "reader"
+	bytesSourceName  = "bytes"
+)
+
 type targetConfigWrapper struct {
 	castedTarget easyjson.MarshalerUnmarshaler `ignored:"true"`
 
 	TargetForPrepare    interface{}
+	sourceReader        io.Reader     `ignored:"true"`
+	sourceFS            fs.FS         `ignored:"true"`
 	sourceFilePath      *string       `ignored:"true"`
 	DependentCfgSrvList []interface{} `ignored:"true"`
 	sourceData          []byte        `ignored:"true"`
 }
 
+// setSource - set source of config document, sources passed by previous calls are reset...
+func (w *targetConfigWrapper) setSource(sourceData []byte, reader io.Reader, fileSystem fs.FS, filePath *string) {
+	w.sourceData = sourceData
+	w.sourceReader = reader
+	w.sourceFS = fileSystem
+	w.sourceFilePath = filePath
+}
+
 type Service struct {
 	e          errorFormatterService
-	secretsSrv secretManagerService
+	secretsSrv secretProviderService
 
 	wrapperConfig *targetConfigWrapper
+
+	includeRootDir  string
+	environmentName string
+
+	overlayPathsList []string
+	mergedDocument   []byte
+
+	maxSourceSize int64
+
+	isJSONCEnabled     bool
+	isUnknownKeysError bool
 }
 
+// WithIncludeRoot - set root directory of files, which can be included by $include and $ref keys.
+// Directory of config file, passed to PrepareFromFile function, used by default. For config file of fs.FS
+// directory is a slash-separated path of fs.FS, root of fs.FS used by default...
+func (m *Service) WithIncludeRoot(dirPath string) *Service {
+	m.includeRootDir = dirPath
+
+	return m
+}
+
+// WithOverlays - set overlay files, which applied over config document in order of list.
+// Overlay with JSON object is JSON Merge Patch (RFC 7396), overlay with JSON array - JSON Patch (RFC 6902).
+// Overlays are applied after environment overlay...
+func (m *Service) WithOverlays(overlayPathsList ...string) *Service {
+	m.overlayPathsList = append(m.overlayPathsList, overlayPathsList...)
+
+	return m
+}
+
+// WithEnvironment - set environment name of environment overlay, e.g. config.production.json for config.json.
+// By default canonical environment name taken from dependency with GetEnvironment function, e.g. BaseConfig...
+func (m *Service) WithEnvironment(environmentName string) *Service {
+	m.environmentName = environmentName
+
+	return m
+}
+
+// MergedDocument returns fully merged document - with resolved $include and $ref keys and applied overlays,
+// formatted for printing. Secrets are not resolved. Will be filled on Do call...
+func (m *Service) MergedDocument() []byte {
+	return m.mergedDocument
+}
+
+// WithStrictKeys - enable strict mode: unknown keys of config structs are reported as errors.
+// Meta keys with "$" prefix, e.g. $min_version, are allowed...
+func (m *Service) WithStrictKeys() *Service {
+	m.isUnknownKeysError = true
+
+	return m
+}
+
+// WithJSONC - enable JSONC mode: source document can contain // and /* */ comments and trailing commas.
+// Files with .jsonc extension are always processed in JSONC mode...
+func (m *Service) WithJSONC() *Service {
+	m.isJSONCEnabled = true
+
+	return m
+}
+
+// PrepareFrom - use passed bytes as config document. Size limit isn't applied to passed bytes,
+// gzip-compressed data is decompressed automatically with size limit of decompressed data...
 func (m *Service) PrepareFrom(rawJSONData []byte) *Service {
-	m.wrapperConfig.sourceData = rawJSONData
+	m.wrapperConfig.setSource(rawJSONData, nil, nil, nil)
 
 	return m
 }
 
 func (m *Service) PrepareFromFile(fileDataPath string) *Service {
-	m.wrapperConfig.sourceFilePath = &fileDataPath
+	m.wrapperConfig.setSource(nil, nil, nil, &fileDataPath)
+
+	return m
+}
+
+// PrepareFromReader - read config document from reader, e.g. os.Stdin. Reader is consumed by Do call.
+// Gzip-compressed data is decompressed automatically...
+func (m *Service) PrepareFromReader(reader io.Reader) *Service {
+	m.wrapperConfig.setSource(nil, reader, nil, nil)
+
+	return m
+}
+
+// PrepareFromFS - read config file from fs.FS, e.g. embed.FS. Files of $include and $ref keys and overlays
+// are read from same fs.FS. Files with .gz extension or gzip header are decompressed automatically...
+func (m *Service) PrepareFromFS(fileSystem fs.FS, filePath string) *Service {
+	m.wrapperConfig.setSource(nil, nil, fileSystem, &filePath)
+
+	return m
+}
+
+// WithMaxSourceSize - set size limit of config sources in bytes, for gzip-compressed sources limit is applied
+// to decompressed data. DefaultMaxSourceSize used by default...
+func (m *Service) WithMaxSourceSize(maxSize int64) *Service {
+	m.maxSourceSize = maxSize
 
 	return m
 }
@@ -72,6 +183,8 @@ func (m *Service) PrepareTo(targetForPrepare interface{}) *Service {
 	wrappedTargetConf := &targetConfigWrapper{
 		DependentCfgSrvList: make([]interface{}, 0),
 		castedTarget:        nil,
+		sourceReader:        nil,
+		sourceFS:            nil,
 		sourceData:          nil,
 		sourceFilePath:      nil,
 		TargetForPrepare:    targetForPrepare,
@@ -90,8 +203,10 @@ func (m *Service) PrepareTo(targetForPrepare interface{}) *Service {
 func (m *Service) With(dependenciesList ...interface{}) *Service {
 	for _, cfgSrv := range dependenciesList {
 		switch castedDependency := cfgSrv.(type) {
-		case secretManagerService:
+		case secretProviderService:
 			m.secretsSrv = castedDependency
+		case secretManagerService:
+			m.secretsSrv = secrets.AdaptSecretManager(castedDependency)
 		case errorFormatterService:
 			m.e = castedDependency
 
@@ -105,26 +220,117 @@ func (m *Service) With(dependenciesList ...interface{}) *Service {
 	return m
 }
 
-func (m *Service) Do(_ context.Context) error {
+func (m *Service) Do(ctx context.Context) error {
+	if m.e == nil {
+		m.e = errfmt.NewStdFormatter()
+	}
+
+	logger := common.NewLoggerByDependencies(m.wrapperConfig.DependentCfgSrvList)
+	isJSONC := m.isJSONCEnabled
+	filePath := ""
+
+	files := newSourceFilesReader(m.wrapperConfig.sourceFS, m.maxSourceSize)
+
 	if m.wrapperConfig.sourceFilePath != nil {
-		rawData, err := os.ReadFile(*m.wrapperConfig.sourceFilePath)
+		filePath = *m.wrapperConfig.sourceFilePath
+		isJSONC = isJSONC || isJSONCFile(filePath)
+	}
+
+	err := m.readSource(logger, files, filePath)
+	if err != nil {
+		return m.e.ErrorOnly(err)
+	}
+
+	sourceData := m.wrapperConfig.sourceData
+
+	if isJSONC {
+		strippedData, err := stripJSONC(sourceData)
+		if err != nil {
+			return m.e.ErrorNoWrap(withFilePath(err, filePath))
+		}
+
+		sourceData = strippedData
+	}
+
+	isTransformed := false
+
+	// origins - files and positions of values of composed document, nil if origins are unknown...
+	var origins documentOrigins
+
+	if isComposable(sourceData) {
+		composedData, composedOrigins, err := m.compose(logger, files, filePath, isJSONC)
+		if err != nil {
+			return m.e.ErrorNoWrap(err)
+		}
+
+		isTransformed = composedData != nil
+		if isTransformed {
+			sourceData, origins = composedData, composedOrigins
+		}
+	}
+
+	overlayPathsList := m.getOverlayPathsList(files, filePath)
+	if len(overlayPathsList) != 0 {
+		overlaidData, overlaidOrigins, err := newDocumentOverlayer(logger, files, isJSONC).
+			Apply(sourceData, filePath, origins, overlayPathsList)
 		if err != nil {
-			return m.e.ErrorOnly(err)
+			return m.e.ErrorNoWrap(err)
+		}
+
+		isTransformed = true
+		sourceData, origins = overlaidData, overlaidOrigins
+	}
+
+	m.mergedDocument = indentDocument(sourceData)
+
+	validatorSvc := newDocumentValidator(sourceData, sourceData, m.isUnknownKeysError)
+	if !isTransformed {
+		validatorSvc.sourceData = m.wrapperConfig.sourceData
+	}
+
+	validationErrorsList, err := validatorSvc.Validate(reflect.TypeOf(m.wrapperConfig.TargetForPrepare))
+	if err != nil {
+		return m.e.ErrorNoWrap(withFilePath(err, filePath))
+	}
+
+	versionCheckerSvc := &versionConstraintChecker{
+		e:               m.e,
+		dependenciesSvc: m.wrapperConfig.DependentCfgSrvList,
+		releaseVersion:  nil,
+	}
+
+	err = versionCheckerSvc.Check(sourceData)
+	if err != nil {
+		return m.e.ErrorNoWrap(err)
+	}
+
+	if len(validationErrorsList) != 0 {
+		for _, validationErr := range validationErrorsList {
+			withFilePath(validationErr, filePath)
+
+			if isTransformed {
+				origins.Locate(validationErr)
+			}
 		}
 
-		m.wrapperConfig.sourceData = rawData
+		return m.e.ErrorNoWrap(errors.Join(validationErrorsList...))
 	}
 
 	JSONLexer := jlexer.Lexer{
-		Data:              m.wrapperConfig.sourceData,
+		Data:              sourceData,
 		UseMultipleErrors: false,
 	}
 
 	m.wrapperConfig.castedTarget.UnmarshalEasyJSON(&JSONLexer)
 
-	err := JSONLexer.Error()
+	err = JSONLexer.Error()
 	if err != nil {
-		return m.e.ErrorOnly(err)
+		return m.e.ErrorNoWrap(newLexerDecodeError(err, validatorSvc.sourceData, filePath))
+	}
+
+	err = m.newFieldsFiller(logger).Process(sourceData)
+	if err != nil {
+		return m.e.ErrorNoWrap(err)
 	}
 
 	secretDataFillerSvc := &secretFiller{
@@ -132,12 +338,174 @@ func (m *Service) Do(_ context.Context) error {
 		dependenciesSvc: m.wrapperConfig.DependentCfgSrvList,
 		secretsDataSvc:  m.secretsSrv,
 		target:          m.wrapperConfig.TargetForPrepare,
+		logger:          logger,
 	}
 
-	err = secretDataFillerSvc.Process()
+	err = secretDataFillerSvc.Process(ctx)
 	if err != nil {
 		return m.e.ErrorNoWrap(err)
 	}
 
 	return nil
 }
+
+// readSource - read source data from file, fs.FS, reader or passed bytes with size limit and decompression...
+func (m *Service) readSource(logger *common.Logger, files *sourceFilesReader, filePath string) error {
+	var (
+		rawData []byte
+		err     error
+	)
+
+	switch {
+	case filePath != "" && files.fileSystem != nil:
+		logger.SourceDiscovered(fsSourceName+":"+filePath, 0)
+
+		rawData, err = files.ReadFile(filePath)
+	case filePath != "":
+		logger.SourceDiscovered(fileSourceName+":"+filePath, 0)
+
+		rawData, err = files.ReadFile(filePath)
+	case m.wrapperConfig.sourceReader != nil:
+		logger.SourceDiscovered(readerSourceName, 0)
+
+		rawData, err = readSource(m.wrapperConfig.sourceReader, m.maxSourceSize)
+	case bytes.HasPrefix(m.wrapperConfig.sourceData, gzipMagicBytes):
+		logger.SourceDiscovered(bytesSourceName, 0)
+
+		rawData, err = readSource(bytes.NewReader(m.wrapperConfig.sourceData), m.maxSourceSize)
+	default:
+		// passed bytes are already in memory - size limit isn't applied
+		logger.SourceDiscovered(bytesSourceName, 0)
+
+		rawData = m.wrapperConfig.sourceData
+	}
+
+	if err != nil {
+		return err
+	}
+
+	m.wrapperConfig.sourceData = rawData
+
+	return nil
+}
+
+// compose - resolve $include and $ref keys of source document, returns composed document and origins of its values...
+func (m *Service) compose(logger *common.Logger,
+	files *sourceFilesReader,
+	filePath string,
+	isJSONC bool,
+) ([]byte, documentOrigins, error) {
+	rootDir := m.includeRootDir
+	if rootDir == "" && filePath != "" && files.fileSystem == nil {
+		rootDir = filepath.Dir(filePath)
+	}
+
+	composerSvc, err := newDocumentComposer(logger, files, rootDir, isJSONC)
+	if err != nil {
+		return nil, nil, m.e.ErrorOnly(err, rootDir)
+	}
+
+	return composerSvc.Compose(m.wrapperConfig.sourceData, filePath)
+}
+
+// resolveEnvironmentName returns WithEnvironment argument or environment name of dependency - canonical name
+// of environment definition of dependency with GetEnvironment function, e.g. BaseConfig, or name of dependency
+// with GetEnvironmentName function. Aliases are replaced by canonical names, if environment names registry passed
+// in dependencies list, e.g. prod -> production...
+func (m *Service) resolveEnvironmentName() string {
+	environmentName := m.environmentName
+	environmentNames := map[string]string(nil)
+
+	for _, dependency := range m.wrapperConfig.DependentCfgSrvList {
+		switch castedDependency := dependency.(type) {
+		case environmentProviderService:
+			environment := castedDependency.GetEnvironment()
+			if environmentName == "" && environment != nil {
+				environmentName = environment.Name
+			}
+
+			environmentNameSvc, isPossibleToCast := dependency.(environmentNameProviderService)
+			if environmentName == "" && isPossibleToCast {
+				environmentName = environmentNameSvc.GetEnvironmentName()
+			}
+		case environmentNameProviderService:
+			if environmentName == "" {
+				environmentName = castedDependency.GetEnvironmentName()
+			}
+		case environmentNamesProviderService:
+			environmentNames = castedDependency.GetNames()
+		default:
+			continue
+		}
+	}
+
+	canonicalName, isKnown := environmentNames[environmentName]
+	if isKnown {
+		return canonicalName
+	}
+
+	return environmentName
+}
+
+// newFieldsFiller - create filler of envconfig, default and required tags by dependencies list.
+// Environment names registry is optional, without registry only exact environment names are matched...
+func (m *Service) newFieldsFiller(logger *common.Logger) *fieldsFiller {
+	environmentName := m.resolveEnvironmentName()
+	environmentNames := map[string]string{environmentName: environmentName}
+	variableSourcesList := make([]variableSourceService, 0)
+
+	for _, dependency := range m.wrapperConfig.DependentCfgSrvList {
+		switch castedDependency := dependency.(type) {
+		case variableSourceService:
+			variableSourcesList = append(variableSourcesList, castedDependency)
+		case environmentNamesProviderService:
+			environmentNames = castedDependency.GetNames()
+		default:
+			continue
+		}
+	}
+
+	return &fieldsFiller{
+		e:                   m.e,
+		logger:              logger,
+		target:              m.wrapperConfig.TargetForPrepare,
+		variableSourcesList: variableSourcesList,
+		environmentName:     environmentName,
+		environmentNames:    environmentNames,
+	}
+}
+
+// getOverlayPathsList returns environment overlay, if it exists, and overlays passed to WithOverlays function...
+func (m *Service) getOverlayPathsList(files *sourceFilesReader, filePath string) []string {
+	environmentName := m.resolveEnvironmentName()
+
+	overlayPathsList := make([]string, 0, len(m.overlayPathsList)+1)
+
+	environmentOverlay := environmentOverlayPath(files, filePath, environmentName)
+	if environmentOverlay != "" {
+		overlayPathsList = append(overlayPathsList, environmentOverlay)
+	}
+
+	return append(overlayPathsList, m.overlayPathsList...)
+}
+
+// NewService - create JSON config service. errFmtSvc is optional - standard library formatter
+// will be used if nil passed. Zero value of Service is also ready to use...
+func NewService(errFmtSvc errorFormatterService) *Service {
+	if errFmtSvc == nil {
+		errFmtSvc = errfmt.NewStdFormatter()
+	}
+
+	return &Service{
+		e:                  errFmtSvc,
+		secretsSrv:         nil,
+		wrapperConfig:      nil, // will be filled by PrepareTo call
+		includeRootDir:     "",
+		environmentName:    "",
+		overlayPathsList:   nil,
+		mergedDocument:     nil,
+		maxSourceSize:      0,
+		isJSONCEnabled:     false,
+		isUnknownKeysError: false,
+	}
+}
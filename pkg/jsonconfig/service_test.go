@@ -34,11 +34,14 @@ package jsonconfig
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
 )
 
 type mockSecretManager struct {
@@ -231,3 +234,97 @@ func TestArrayJSONStructWithSecret(t *testing.T) {
 		t.Errorf("GetPort not equal")
 	}
 }
+
+func TestServiceDefaultErrFormatter(t *testing.T) {
+	err := (&Service{}).PrepareTo(&SimpleJSONCase{}).PrepareFrom([]byte(`{"int_field_one": "wrong"`)).
+		Do(context.Background())
+	if err == nil {
+		t.Errorf("expected error for invalid JSON")
+	}
+
+	unmarshaledData := &SimpleJSONCase{}
+
+	err = NewService(nil).PrepareTo(unmarshaledData).PrepareFrom([]byte(`{"int_field_one": 1, "db_port": "5432"}`)).
+		Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if unmarshaledData.IntFieldOne != 1 || unmarshaledData.GetPort() != 5432 {
+		t.Errorf("not equal unmarshaled data")
+	}
+}
+
+func TestServiceTypedFieldErrors(t *testing.T) {
+	rawData := []byte(`{"list": [{"db_port": "1"}, {"db_port": "2", "db_user": "!secret:UNKNOWN_USER"}]}`)
+
+	err := NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFrom(rawData).
+		With(&mockSecretManager{ValuesPool: map[string]string{}}).
+		Do(context.Background())
+
+	var fieldErr *common.FieldError
+	if !errors.As(err, &fieldErr) || !errors.Is(err, ErrVariableEmptyButRequired) {
+		t.Errorf("expected field error of required rule: %v", err)
+		return
+	}
+
+	if fieldErr.Path != "MixedJSONCase.List[1].DBUser" || fieldErr.SecretKey != "UNKNOWN_USER" {
+		t.Errorf("not equal field path or secret key: %s", fieldErr.Path)
+	}
+}
+
+var errSecretStorageUnavailable = errors.New("secret storage unavailable")
+
+type failingSecretProvider struct {
+	failedKeys map[string]bool
+	keysList   []string
+}
+
+func (m *failingSecretProvider) Get(_ context.Context, key string) (secrets.Secret, error) {
+	if m.failedKeys[key] {
+		return secrets.Secret{}, errSecretStorageUnavailable
+	}
+
+	return secrets.Secret{Key: key, Value: "value"}, nil
+}
+
+func (m *failingSecretProvider) GetMany(ctx context.Context, keysList []string) (map[string]secrets.Secret, error) {
+	m.keysList = keysList
+
+	return secrets.GetManyConcurrently(ctx, m, keysList, 2)
+}
+
+func TestServiceSecretsBatchResolution(t *testing.T) {
+	rawData := []byte(`{"list": [
+		{"db_port": "1", "db_user": "!secret:USER", "db_password": "!secret:PASSWORD_1"},
+		{"db_port": "2", "db_user": "!secret:USER", "db_password": "!secret:PASSWORD_2"}
+	]}`)
+
+	provider := &failingSecretProvider{
+		failedKeys: map[string]bool{"PASSWORD_1": true, "PASSWORD_2": true},
+		keysList:   nil,
+	}
+
+	err := NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFrom(rawData).With(provider).
+		Do(context.Background())
+	if !errors.Is(err, errSecretStorageUnavailable) {
+		t.Errorf("expected secret storage error: %v", err)
+		return
+	}
+
+	if strings.Join(provider.keysList, ",") != "USER,PASSWORD_1,PASSWORD_2" {
+		t.Errorf("not equal discovered keys: %v", provider.keysList)
+	}
+
+	var joinedErr interface{ Unwrap() []error }
+	if !errors.As(err, &joinedErr) || len(joinedErr.Unwrap()) != 2 {
+		t.Errorf("expected aggregated errors of both failed fields: %v", err)
+		return
+	}
+
+	var fieldErr *common.FieldError
+	if !errors.As(joinedErr.Unwrap()[1], &fieldErr) || fieldErr.Path != "MixedJSONCase.List[1].DBPassword" {
+		t.Errorf("not equal order of aggregated errors: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var ErrSourceIsTooLarge = errors.New("config source is too large")
+
+const (
+	// DefaultMaxSourceSize - default limit of config source size, for gzip-compressed sources
+	// limit is applied to decompressed data...
+	DefaultMaxSourceSize int64 = 16 << 20
+
+	gzipFileExtension = ".gz"
+)
+
+// gzipMagicBytes - header of gzip-compressed data...
+var gzipMagicBytes = []byte{0x1f, 0x8b}
+
+// sourceFilesReader - reader of config files from OS file system or from fs.FS, e.g. embed.FS.
+// Paths of fs.FS are slash-separated and relative to root of fs.FS...
+type sourceFilesReader struct {
+	fileSystem fs.FS
+	maxSize    int64
+}
+
+// ReadFile - read config file with size limit and decompress it, if file is gzip-compressed...
+func (r *sourceFilesReader) ReadFile(filePath string) ([]byte, error) {
+	var (
+		file io.ReadCloser
+		err  error
+	)
+
+	if r.fileSystem != nil {
+		file, err = r.fileSystem.Open(filePath)
+	} else {
+		file, err = os.Open(filePath)
+	}
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // it's ok, file opening error
+	}
+
+	defer func() { _ = file.Close() }()
+
+	data, err := readSource(file, r.maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	return data, nil
+}
+
+// IsFileExists - check that regular file exists...
+func (r *sourceFilesReader) IsFileExists(filePath string) bool {
+	var (
+		fileInfo fs.FileInfo
+		err      error
+	)
+
+	if r.fileSystem != nil {
+		fileInfo, err = fs.Stat(r.fileSystem, filePath)
+	} else {
+		fileInfo, err = os.Stat(filePath)
+	}
+
+	return err == nil && !fileInfo.IsDir()
+}
+
+// Dir returns directory of path of file system...
+func (r *sourceFilesReader) Dir(filePath string) string {
+	if r.fileSystem != nil {
+		return path.Dir(filePath)
+	}
+
+	return filepath.Dir(filePath)
+}
+
+// readSource - read config source with size limit. Gzip-compressed data detected by header and decompressed...
+func readSource(reader io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSourceSize
+	}
+
+	bufferedReader := bufio.NewReader(reader)
+	sourceReader := io.Reader(bufferedReader)
+
+	header, _ := bufferedReader.Peek(len(gzipMagicBytes))
+	if bytes.Equal(header, gzipMagicBytes) {
+		gzipReader, err := gzip.NewReader(bufferedReader)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // it's ok, gzip error
+		}
+
+		defer func() { _ = gzipReader.Close() }()
+
+		sourceReader = gzipReader
+	}
+
+	data, err := io.ReadAll(io.LimitReader(sourceReader, maxSize+1))
+	if err != nil {
+		return nil, err //nolint:wrapcheck // it's ok, reading error
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrSourceIsTooLarge, maxSize)
+	}
+
+	return data, nil
+}
+
+// trimGzipExtension - remove .gz extension of file path, e.g. config.jsonc.gz -> config.jsonc...
+func trimGzipExtension(filePath string) string {
+	if strings.EqualFold(filepath.Ext(filePath), gzipFileExtension) {
+		return filePath[:len(filePath)-len(gzipFileExtension)]
+	}
+
+	return filePath
+}
+
+func newSourceFilesReader(fileSystem fs.FS, maxSize int64) *sourceFilesReader {
+	return &sourceFilesReader{
+		fileSystem: fileSystem,
+		maxSize:    maxSize,
+	}
+}
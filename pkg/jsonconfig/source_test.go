@@ -0,0 +1,164 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func gzipTestData(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buffer)
+
+	_, err := gzipWriter.Write([]byte(data))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	err = gzipWriter.Close()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestServicePrepareFromReader(t *testing.T) {
+	cfg := &MixedJSONCase{}
+
+	err := NewService(nil).PrepareTo(cfg).
+		PrepareFromReader(strings.NewReader(`{"top_level_field_int": 1, "list": [{"db_port": "1"}]}`)).
+		Do(context.Background())
+	if err != nil || cfg.TopLevelField != 1 || cfg.List[0].GetPort() != 1 {
+		t.Errorf("not equal config of reader: %v", err)
+	}
+
+	cfg = &MixedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).
+		PrepareFromReader(bytes.NewReader(gzipTestData(t, `{"top_level_field_int": 2}`))).
+		Do(context.Background())
+	if err != nil || cfg.TopLevelField != 2 {
+		t.Errorf("not equal config of gzip-compressed reader: %v", err)
+	}
+
+	err = NewService(nil).PrepareTo(&MixedJSONCase{}).
+		PrepareFromReader(bytes.NewReader(gzipTestData(t, `{"top_level_field_int": 100500}`))).
+		WithMaxSourceSize(16).
+		Do(context.Background())
+	if !errors.Is(err, ErrSourceIsTooLarge) {
+		t.Errorf("expected too large source error: %v", err)
+	}
+
+	cfg = &MixedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).PrepareFrom([]byte(`{"top_level_field_int": 100500}`)).
+		WithMaxSourceSize(16).
+		Do(context.Background())
+	if err != nil || cfg.TopLevelField != 100500 {
+		t.Errorf("size limit must not be applied to passed bytes: %v", err)
+	}
+
+	cfg = &MixedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).PrepareFromFile("not-existing-config.json").
+		PrepareFromReader(strings.NewReader(`{"top_level_field_int": 3}`)).
+		Do(context.Background())
+	if err != nil || cfg.TopLevelField != 3 {
+		t.Errorf("reader must replace previously passed file source: %v", err)
+	}
+}
+
+func TestServicePrepareFromFS(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"configs/config.jsonc": &fstest.MapFile{
+			Data: []byte(`{
+				// embedded defaults
+				"$include": "common/base.json.gz",
+				"list": [{"$ref": "../nodes/btc.json"}],
+			}`),
+		},
+		"configs/config.production.jsonc": &fstest.MapFile{Data: []byte(`{"top_level_field_int": 3}`)},
+		"configs/common/base.json.gz":     &fstest.MapFile{Data: gzipTestData(t, `{"top_level_field_int": 1}`)},
+		"nodes/btc.json":                  &fstest.MapFile{Data: []byte(`{"db_port": "8332"}`)},
+		"configs/escape.json":             &fstest.MapFile{Data: []byte(`{"$include": "../../outside.json"}`)},
+	}
+
+	cfg := &MixedJSONCase{}
+	svc := NewService(nil).PrepareTo(cfg).PrepareFromFS(fileSystem, "configs/config.jsonc")
+
+	err := svc.Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.TopLevelField != 1 || len(cfg.List) != 1 || cfg.List[0].GetPort() != 8332 {
+		t.Errorf("not equal config of fs: %+v", cfg)
+	}
+
+	cfg = &MixedJSONCase{}
+
+	err = NewService(nil).PrepareTo(cfg).PrepareFromFS(fileSystem, "configs/config.jsonc").
+		WithEnvironment("production").Do(context.Background())
+	if err != nil || cfg.TopLevelField != 3 {
+		t.Errorf("expected environment overlay of fs: %v", err)
+	}
+
+	err = NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFromFS(fileSystem, "configs/escape.json").
+		Do(context.Background())
+	if !errors.Is(err, ErrIncludeOutsideRoot) {
+		t.Errorf("expected include outside root error: %v", err)
+	}
+
+	err = NewService(nil).WithIncludeRoot("configs").PrepareTo(&MixedJSONCase{}).
+		PrepareFromFS(fileSystem, "configs/config.jsonc").Do(context.Background())
+	if !errors.Is(err, ErrIncludeOutsideRoot) {
+		t.Errorf("expected include outside of include root error: %v", err)
+	}
+
+	err = NewService(nil).WithIncludeRoot("../configs").PrepareTo(&MixedJSONCase{}).
+		PrepareFromFS(fileSystem, "configs/config.jsonc").Do(context.Background())
+	if !errors.Is(err, ErrWrongIncludeRoot) {
+		t.Errorf("expected wrong include root error: %v", err)
+	}
+}
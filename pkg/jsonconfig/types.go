@@ -34,6 +34,9 @@ package jsonconfig
 
 import (
 	"strconv"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/config"
 
 	_ "github.com/mailru/easyjson/gen"
 )
@@ -65,6 +68,68 @@ type SimpleJSONCase struct {
 	dbPortAsInt uint32 `json:"-"`
 }
 
+// easyjson:json
+type taggedJSONCase struct {
+	Host      string            `json:"host" envconfig:"TAGGED_CASE_HOST"`
+	Port      int               `json:"port" default:"8080"`
+	Retries   int               `json:"retries" default:"3"`
+	Token     string            `json:"token" required_in:"production"`
+	NodesList []taggedNodeCase  `json:"nodes"`
+	Limits    *taggedLimitsCase `json:"limits"`
+}
+
+type taggedNodeCase struct {
+	Name    string        `json:"name" required:"true"`
+	Timeout time.Duration `json:"timeout" default:"5s" default_production:"30s"`
+}
+
+type taggedLimitsCase struct {
+	MaxCount int `json:"max_count" envconfig:"TAGGED_CASE_MAX_COUNT" default:"10"`
+}
+
+type secretNodeCase struct {
+	Password string `json:"password" secret:"true"`
+	Address  string `json:"address"`
+
+	preparedCount int
+}
+
+// Prepare - count of Prepare calls is used for checking of single preparation of map values...
+func (v *secretNodeCase) Prepare() error {
+	v.preparedCount++
+
+	return nil
+}
+
+func (v *secretNodeCase) PrepareWith(_ ...interface{}) error {
+	return nil
+}
+
+type secretTLSCase struct {
+	KeyPassword *string `json:"key_password" secret:"true"`
+}
+
+// easyjson:json
+type nestedSecretsJSONCase struct {
+	NodesMap      map[string]secretNodeCase  `json:"nodes"`
+	NodePtrsMap   map[string]*secretNodeCase `json:"node_ptrs"`
+	APIKeysList   []string                   `json:"api_keys" secret:"true"`
+	TokensMap     map[string]string          `json:"tokens" secret:"true"`
+	ShardsList    [][]string                 `json:"shards" secret:"true"`
+	TLS           *secretTLSCase             `json:"tls"`
+	PublicList    []string                   `json:"public"`
+	WrongTypeList []int                      `json:"wrong_type" secret:"true"`
+}
+
+// easyjson:json
+type valueTypesJSONCase struct {
+	MaxBlockSize config.ByteSize `json:"max_block_size"`
+	CacheSize    config.ByteSize `json:"cache_size" default:"64MiB"`
+	Multiplier   config.Percent  `json:"multiplier"`
+	RPCRate      config.Rate     `json:"rpc_rate" envconfig:"VALUE_TYPES_CASE_RPC_RATE"`
+	RatesList    []config.Rate   `json:"rates"`
+}
+
 func (v *SimpleJSONCase) GetPort() uint32 {
 	return v.dbPortAsInt
 }
@@ -4,9 +4,11 @@ package jsonconfig
 
 import (
 	json "encoding/json"
+	config "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/config"
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
 )
 
 // suppress unused package warning
@@ -17,7 +19,871 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(in *jlexer.Lexer, out *SimpleJSONCase) {
+func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(in *jlexer.Lexer, out *valueTypesJSONCase) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "max_block_size":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.MaxBlockSize).UnmarshalJSON(data))
+			}
+		case "cache_size":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CacheSize).UnmarshalJSON(data))
+			}
+		case "multiplier":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Multiplier).UnmarshalJSON(data))
+			}
+		case "rpc_rate":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.RPCRate).UnmarshalJSON(data))
+			}
+		case "rates":
+			if in.IsNull() {
+				in.Skip()
+				out.RatesList = nil
+			} else {
+				in.Delim('[')
+				if out.RatesList == nil {
+					if !in.IsDelim(']') {
+						out.RatesList = make([]config.Rate, 0, 4)
+					} else {
+						out.RatesList = []config.Rate{}
+					}
+				} else {
+					out.RatesList = (out.RatesList)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 config.Rate
+					if data := in.Raw(); in.Ok() {
+						in.AddError((v1).UnmarshalJSON(data))
+					}
+					out.RatesList = append(out.RatesList, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(out *jwriter.Writer, in valueTypesJSONCase) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"max_block_size\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.MaxBlockSize).MarshalText())
+	}
+	{
+		const prefix string = ",\"cache_size\":"
+		out.RawString(prefix)
+		out.RawText((in.CacheSize).MarshalText())
+	}
+	{
+		const prefix string = ",\"multiplier\":"
+		out.RawString(prefix)
+		out.RawText((in.Multiplier).MarshalText())
+	}
+	{
+		const prefix string = ",\"rpc_rate\":"
+		out.RawString(prefix)
+		out.RawText((in.RPCRate).MarshalText())
+	}
+	{
+		const prefix string = ",\"rates\":"
+		out.RawString(prefix)
+		if in.RatesList == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.RatesList {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.RawText((v3).MarshalText())
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v valueTypesJSONCase) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v valueTypesJSONCase) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *valueTypesJSONCase) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *valueTypesJSONCase) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(l, v)
+}
+func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(in *jlexer.Lexer, out *taggedJSONCase) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "host":
+			out.Host = string(in.String())
+		case "port":
+			out.Port = int(in.Int())
+		case "retries":
+			out.Retries = int(in.Int())
+		case "token":
+			out.Token = string(in.String())
+		case "nodes":
+			if in.IsNull() {
+				in.Skip()
+				out.NodesList = nil
+			} else {
+				in.Delim('[')
+				if out.NodesList == nil {
+					if !in.IsDelim(']') {
+						out.NodesList = make([]taggedNodeCase, 0, 2)
+					} else {
+						out.NodesList = []taggedNodeCase{}
+					}
+				} else {
+					out.NodesList = (out.NodesList)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 taggedNodeCase
+					easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig2(in, &v4)
+					out.NodesList = append(out.NodesList, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "limits":
+			if in.IsNull() {
+				in.Skip()
+				out.Limits = nil
+			} else {
+				if out.Limits == nil {
+					out.Limits = new(taggedLimitsCase)
+				}
+				easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig3(in, out.Limits)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(out *jwriter.Writer, in taggedJSONCase) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"host\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Host))
+	}
+	{
+		const prefix string = ",\"port\":"
+		out.RawString(prefix)
+		out.Int(int(in.Port))
+	}
+	{
+		const prefix string = ",\"retries\":"
+		out.RawString(prefix)
+		out.Int(int(in.Retries))
+	}
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix)
+		out.String(string(in.Token))
+	}
+	{
+		const prefix string = ",\"nodes\":"
+		out.RawString(prefix)
+		if in.NodesList == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.NodesList {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig2(out, v6)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"limits\":"
+		out.RawString(prefix)
+		if in.Limits == nil {
+			out.RawString("null")
+		} else {
+			easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig3(out, *in.Limits)
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v taggedJSONCase) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v taggedJSONCase) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *taggedJSONCase) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *taggedJSONCase) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(l, v)
+}
+func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig3(in *jlexer.Lexer, out *taggedLimitsCase) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "max_count":
+			out.MaxCount = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig3(out *jwriter.Writer, in taggedLimitsCase) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"max_count\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.MaxCount))
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig2(in *jlexer.Lexer, out *taggedNodeCase) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "timeout":
+			out.Timeout = time.Duration(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig2(out *jwriter.Writer, in taggedNodeCase) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"timeout\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Timeout))
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig4(in *jlexer.Lexer, out *nestedSecretsJSONCase) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nodes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.NodesMap = make(map[string]secretNodeCase)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v7 secretNodeCase
+					easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig5(in, &v7)
+					(out.NodesMap)[key] = v7
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "node_ptrs":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.NodePtrsMap = make(map[string]*secretNodeCase)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v8 *secretNodeCase
+					if in.IsNull() {
+						in.Skip()
+						v8 = nil
+					} else {
+						if v8 == nil {
+							v8 = new(secretNodeCase)
+						}
+						easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig5(in, v8)
+					}
+					(out.NodePtrsMap)[key] = v8
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "api_keys":
+			if in.IsNull() {
+				in.Skip()
+				out.APIKeysList = nil
+			} else {
+				in.Delim('[')
+				if out.APIKeysList == nil {
+					if !in.IsDelim(']') {
+						out.APIKeysList = make([]string, 0, 4)
+					} else {
+						out.APIKeysList = []string{}
+					}
+				} else {
+					out.APIKeysList = (out.APIKeysList)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v9 string
+					v9 = string(in.String())
+					out.APIKeysList = append(out.APIKeysList, v9)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "tokens":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.TokensMap = make(map[string]string)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v10 string
+					v10 = string(in.String())
+					(out.TokensMap)[key] = v10
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "shards":
+			if in.IsNull() {
+				in.Skip()
+				out.ShardsList = nil
+			} else {
+				in.Delim('[')
+				if out.ShardsList == nil {
+					if !in.IsDelim(']') {
+						out.ShardsList = make([][]string, 0, 2)
+					} else {
+						out.ShardsList = [][]string{}
+					}
+				} else {
+					out.ShardsList = (out.ShardsList)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v11 []string
+					if in.IsNull() {
+						in.Skip()
+						v11 = nil
+					} else {
+						in.Delim('[')
+						if v11 == nil {
+							if !in.IsDelim(']') {
+								v11 = make([]string, 0, 4)
+							} else {
+								v11 = []string{}
+							}
+						} else {
+							v11 = (v11)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v12 string
+							v12 = string(in.String())
+							v11 = append(v11, v12)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.ShardsList = append(out.ShardsList, v11)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "tls":
+			if in.IsNull() {
+				in.Skip()
+				out.TLS = nil
+			} else {
+				if out.TLS == nil {
+					out.TLS = new(secretTLSCase)
+				}
+				easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig6(in, out.TLS)
+			}
+		case "public":
+			if in.IsNull() {
+				in.Skip()
+				out.PublicList = nil
+			} else {
+				in.Delim('[')
+				if out.PublicList == nil {
+					if !in.IsDelim(']') {
+						out.PublicList = make([]string, 0, 4)
+					} else {
+						out.PublicList = []string{}
+					}
+				} else {
+					out.PublicList = (out.PublicList)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 string
+					v13 = string(in.String())
+					out.PublicList = append(out.PublicList, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "wrong_type":
+			if in.IsNull() {
+				in.Skip()
+				out.WrongTypeList = nil
+			} else {
+				in.Delim('[')
+				if out.WrongTypeList == nil {
+					if !in.IsDelim(']') {
+						out.WrongTypeList = make([]int, 0, 8)
+					} else {
+						out.WrongTypeList = []int{}
+					}
+				} else {
+					out.WrongTypeList = (out.WrongTypeList)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v14 int
+					v14 = int(in.Int())
+					out.WrongTypeList = append(out.WrongTypeList, v14)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig4(out *jwriter.Writer, in nestedSecretsJSONCase) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"nodes\":"
+		out.RawString(prefix[1:])
+		if in.NodesMap == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v15First := true
+			for v15Name, v15Value := range in.NodesMap {
+				if v15First {
+					v15First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v15Name))
+				out.RawByte(':')
+				easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig5(out, v15Value)
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"node_ptrs\":"
+		out.RawString(prefix)
+		if in.NodePtrsMap == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v16First := true
+			for v16Name, v16Value := range in.NodePtrsMap {
+				if v16First {
+					v16First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v16Name))
+				out.RawByte(':')
+				if v16Value == nil {
+					out.RawString("null")
+				} else {
+					easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig5(out, *v16Value)
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"api_keys\":"
+		out.RawString(prefix)
+		if in.APIKeysList == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.APIKeysList {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v18))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"tokens\":"
+		out.RawString(prefix)
+		if in.TokensMap == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v19First := true
+			for v19Name, v19Value := range in.TokensMap {
+				if v19First {
+					v19First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v19Name))
+				out.RawByte(':')
+				out.String(string(v19Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"shards\":"
+		out.RawString(prefix)
+		if in.ShardsList == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v20, v21 := range in.ShardsList {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				if v21 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v22, v23 := range v21 {
+						if v22 > 0 {
+							out.RawByte(',')
+						}
+						out.String(string(v23))
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"tls\":"
+		out.RawString(prefix)
+		if in.TLS == nil {
+			out.RawString("null")
+		} else {
+			easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig6(out, *in.TLS)
+		}
+	}
+	{
+		const prefix string = ",\"public\":"
+		out.RawString(prefix)
+		if in.PublicList == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v24, v25 := range in.PublicList {
+				if v24 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v25))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"wrong_type\":"
+		out.RawString(prefix)
+		if in.WrongTypeList == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v26, v27 := range in.WrongTypeList {
+				if v26 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v27))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v nestedSecretsJSONCase) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v nestedSecretsJSONCase) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *nestedSecretsJSONCase) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *nestedSecretsJSONCase) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig4(l, v)
+}
+func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig6(in *jlexer.Lexer, out *secretTLSCase) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "key_password":
+			if in.IsNull() {
+				in.Skip()
+				out.KeyPassword = nil
+			} else {
+				if out.KeyPassword == nil {
+					out.KeyPassword = new(string)
+				}
+				*out.KeyPassword = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig6(out *jwriter.Writer, in secretTLSCase) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"key_password\":"
+		out.RawString(prefix[1:])
+		if in.KeyPassword == nil {
+			out.RawString("null")
+		} else {
+			out.String(string(*in.KeyPassword))
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig5(in *jlexer.Lexer, out *secretNodeCase) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "password":
+			out.Password = string(in.String())
+		case "address":
+			out.Address = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig5(out *jwriter.Writer, in secretNodeCase) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Password))
+	}
+	{
+		const prefix string = ",\"address\":"
+		out.RawString(prefix)
+		out.String(string(in.Address))
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig7(in *jlexer.Lexer, out *SimpleJSONCase) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -36,16 +902,8 @@ func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonco
 			continue
 		}
 		switch key {
-		case "int_field_one":
-			out.IntFieldOne = int(in.Int())
-		case "int_field_tow":
-			out.IntFieldTwo = int(in.Int())
-		case "int_field_three":
-			out.IntFieldThree = int(in.Int())
 		case "string_field":
 			out.StringField = string(in.String())
-		case "float_field":
-			out.FloatField = float32(in.Float32())
 		case "db_user":
 			out.DBUser = string(in.String())
 		case "db_password":
@@ -54,6 +912,14 @@ func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonco
 			out.DBName = string(in.String())
 		case "db_port":
 			out.DBPort = string(in.String())
+		case "int_field_one":
+			out.IntFieldOne = int(in.Int())
+		case "int_field_tow":
+			out.IntFieldTwo = int(in.Int())
+		case "int_field_three":
+			out.IntFieldThree = int(in.Int())
+		case "float_field":
+			out.FloatField = float32(in.Float32())
 		default:
 			in.SkipRecursive()
 		}
@@ -64,54 +930,54 @@ func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonco
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(out *jwriter.Writer, in SimpleJSONCase) {
+func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig7(out *jwriter.Writer, in SimpleJSONCase) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"int_field_one\":"
+		const prefix string = ",\"string_field\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.IntFieldOne))
+		out.String(string(in.StringField))
 	}
 	{
-		const prefix string = ",\"int_field_tow\":"
+		const prefix string = ",\"db_user\":"
 		out.RawString(prefix)
-		out.Int(int(in.IntFieldTwo))
+		out.String(string(in.DBUser))
 	}
 	{
-		const prefix string = ",\"int_field_three\":"
+		const prefix string = ",\"db_password\":"
 		out.RawString(prefix)
-		out.Int(int(in.IntFieldThree))
+		out.String(string(in.DBPassword))
 	}
 	{
-		const prefix string = ",\"string_field\":"
+		const prefix string = ",\"db_name\":"
 		out.RawString(prefix)
-		out.String(string(in.StringField))
+		out.String(string(in.DBName))
 	}
 	{
-		const prefix string = ",\"float_field\":"
+		const prefix string = ",\"db_port\":"
 		out.RawString(prefix)
-		out.Float32(float32(in.FloatField))
+		out.String(string(in.DBPort))
 	}
 	{
-		const prefix string = ",\"db_user\":"
+		const prefix string = ",\"int_field_one\":"
 		out.RawString(prefix)
-		out.String(string(in.DBUser))
+		out.Int(int(in.IntFieldOne))
 	}
 	{
-		const prefix string = ",\"db_password\":"
+		const prefix string = ",\"int_field_tow\":"
 		out.RawString(prefix)
-		out.String(string(in.DBPassword))
+		out.Int(int(in.IntFieldTwo))
 	}
 	{
-		const prefix string = ",\"db_name\":"
+		const prefix string = ",\"int_field_three\":"
 		out.RawString(prefix)
-		out.String(string(in.DBName))
+		out.Int(int(in.IntFieldThree))
 	}
 	{
-		const prefix string = ",\"db_port\":"
+		const prefix string = ",\"float_field\":"
 		out.RawString(prefix)
-		out.String(string(in.DBPort))
+		out.Float32(float32(in.FloatField))
 	}
 	out.RawByte('}')
 }
@@ -119,27 +985,27 @@ func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonco
 // MarshalJSON supports json.Marshaler interface
 func (v SimpleJSONCase) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(&w, v)
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig7(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v SimpleJSONCase) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(w, v)
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig7(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *SimpleJSONCase) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(&r, v)
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig7(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *SimpleJSONCase) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig(l, v)
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig7(l, v)
 }
-func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(in *jlexer.Lexer, out *MixedJSONCase) {
+func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig8(in *jlexer.Lexer, out *MixedJSONCase) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -158,8 +1024,6 @@ func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonco
 			continue
 		}
 		switch key {
-		case "top_level_field_int":
-			out.TopLevelField = uint32(in.Uint32())
 		case "list":
 			if in.IsNull() {
 				in.Skip()
@@ -176,21 +1040,23 @@ func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonco
 					out.List = (out.List)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v1 *SimpleJSONCase
+					var v28 *SimpleJSONCase
 					if in.IsNull() {
 						in.Skip()
-						v1 = nil
+						v28 = nil
 					} else {
-						if v1 == nil {
-							v1 = new(SimpleJSONCase)
+						if v28 == nil {
+							v28 = new(SimpleJSONCase)
 						}
-						(*v1).UnmarshalEasyJSON(in)
+						(*v28).UnmarshalEasyJSON(in)
 					}
-					out.List = append(out.List, v1)
+					out.List = append(out.List, v28)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "top_level_field_int":
+			out.TopLevelField = uint32(in.Uint32())
 		default:
 			in.SkipRecursive()
 		}
@@ -201,58 +1067,58 @@ func easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonco
 		in.Consumed()
 	}
 }
-func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(out *jwriter.Writer, in MixedJSONCase) {
+func easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig8(out *jwriter.Writer, in MixedJSONCase) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"top_level_field_int\":"
-		out.RawString(prefix[1:])
-		out.Uint32(uint32(in.TopLevelField))
-	}
 	{
 		const prefix string = ",\"list\":"
-		out.RawString(prefix)
+		out.RawString(prefix[1:])
 		if in.List == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v2, v3 := range in.List {
-				if v2 > 0 {
+			for v29, v30 := range in.List {
+				if v29 > 0 {
 					out.RawByte(',')
 				}
-				if v3 == nil {
+				if v30 == nil {
 					out.RawString("null")
 				} else {
-					(*v3).MarshalEasyJSON(out)
+					(*v30).MarshalEasyJSON(out)
 				}
 			}
 			out.RawByte(']')
 		}
 	}
+	{
+		const prefix string = ",\"top_level_field_int\":"
+		out.RawString(prefix)
+		out.Uint32(uint32(in.TopLevelField))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v MixedJSONCase) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(&w, v)
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig8(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v MixedJSONCase) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(w, v)
+	easyjson6601e8cdEncodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig8(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *MixedJSONCase) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(&r, v)
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig8(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *MixedJSONCase) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig1(l, v)
+	easyjson6601e8cdDecodeGithubComCryptoBundleBcWalletCommonLibConfigPkgJsonconfig8(l, v)
 }
@@ -0,0 +1,191 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+var (
+	// ErrReleaseVersionIsNotDefined - alias of common.ErrReleaseVersionIsNotDefined error...
+	ErrReleaseVersionIsNotDefined = common.ErrReleaseVersionIsNotDefined
+	ErrWrongVersionConstraintType = errors.New("version constraint must be a string")
+)
+
+const (
+	// MetaKeyMinVersion - JSON object key with minimal release version of application binary,
+	// which can consume object...
+	MetaKeyMinVersion = "$min_version"
+	// MetaKeyMaxVersion - JSON object key with maximal release version of application binary,
+	// which can consume object...
+	MetaKeyMaxVersion = "$max_version"
+)
+
+// versionConstraintChecker - checks $min_version and $max_version keys of JSON objects on any nesting level.
+// Release version of application binary provided by ldflag manager or BaseConfig in dependencies list...
+type versionConstraintChecker struct {
+	e               errorFormatterService
+	dependenciesSvc []interface{}
+	releaseVersion  *common.SemVer
+}
+
+func (c *versionConstraintChecker) Check(rawJSONData []byte) error {
+	if !bytes.Contains(rawJSONData, []byte(MetaKeyMinVersion)) &&
+		!bytes.Contains(rawJSONData, []byte(MetaKeyMaxVersion)) {
+		return nil
+	}
+
+	var document interface{}
+
+	err := json.Unmarshal(rawJSONData, &document)
+	if err != nil {
+		return c.e.ErrorOnly(err)
+	}
+
+	return c.checkValue(document, "")
+}
+
+func (c *versionConstraintChecker) checkValue(value interface{}, pointer string) error {
+	switch castedValue := value.(type) {
+	case map[string]interface{}:
+		err := c.checkObject(castedValue, pointer)
+		if err != nil {
+			return err
+		}
+
+		keysList := make([]string, 0, len(castedValue))
+		for key := range castedValue {
+			keysList = append(keysList, key)
+		}
+
+		sort.Strings(keysList)
+
+		for _, key := range keysList {
+			err = c.checkValue(castedValue[key], pointer+"/"+escapeJSONPointerToken(key))
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range castedValue {
+			err := c.checkValue(item, pointer+"/"+strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *versionConstraintChecker) checkObject(object map[string]interface{}, pointer string) error {
+	minVersion, hasMinVersion, err := c.lookupConstraint(object, MetaKeyMinVersion, pointer)
+	if err != nil {
+		return err
+	}
+
+	maxVersion, hasMaxVersion, err := c.lookupConstraint(object, MetaKeyMaxVersion, pointer)
+	if err != nil {
+		return err
+	}
+
+	if !hasMinVersion && !hasMaxVersion {
+		return nil
+	}
+
+	releaseVersion, err := c.getReleaseVersion()
+	if err != nil {
+		return err
+	}
+
+	err = common.CheckVersionConstraint(releaseVersion, minVersion, maxVersion)
+	if err != nil {
+		return c.e.Error(err, "json pointer", pointer)
+	}
+
+	return nil
+}
+
+// lookupConstraint returns string value of version constraint key. Value of other JSON type is an error...
+func (c *versionConstraintChecker) lookupConstraint(object map[string]interface{},
+	key, pointer string,
+) (string, bool, error) {
+	rawValue, isExists := object[key]
+	if !isExists {
+		return "", false, nil
+	}
+
+	value, isString := rawValue.(string)
+	if !isString {
+		return "", false, c.e.ErrorOnly(ErrWrongVersionConstraintType, "json pointer",
+			pointer+"/"+escapeJSONPointerToken(key))
+	}
+
+	return value, true, nil
+}
+
+func (c *versionConstraintChecker) getReleaseVersion() (*common.SemVer, error) {
+	if c.releaseVersion != nil {
+		return c.releaseVersion, nil
+	}
+
+	for _, dependencySvc := range c.dependenciesSvc {
+		castedVersionSvc, isPossibleToCast := dependencySvc.(releaseVersionProviderService)
+		if !isPossibleToCast {
+			continue
+		}
+
+		releaseVersion, err := castedVersionSvc.GetReleaseVersion()
+		if err != nil {
+			return nil, c.e.ErrorNoWrap(err)
+		}
+
+		c.releaseVersion = releaseVersion
+
+		return releaseVersion, nil
+	}
+
+	return nil, c.e.ErrorOnly(ErrReleaseVersionIsNotDefined, MetaKeyMinVersion, MetaKeyMaxVersion)
+}
+
+// escapeJSONPointerToken - escape object key for usage in JSON pointer, see RFC 6901...
+func escapeJSONPointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,107 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package jsonconfig
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+type mockReleaseVersionProvider struct {
+	releaseTag string
+}
+
+func (m *mockReleaseVersionProvider) GetReleaseVersion() (*common.SemVer, error) {
+	return common.ParseSemVer(m.releaseTag)
+}
+
+func TestJSONVersionConstraints(t *testing.T) {
+	rawData := []byte(`{
+		"$min_version": "v1.2.0",
+		"top_level_field_int": 5,
+		"list": [
+			{"int_field_one": 1, "db_port": "5432"},
+			{"$max_version": "v1.3.0", "int_field_one": 2, "db_port": "5433"}
+		]
+	}`)
+
+	versionsList := []struct {
+		releaseTag string
+		isValid    bool
+	}{
+		{releaseTag: "v1.2.0", isValid: true},
+		{releaseTag: "v1.3.0", isValid: true},
+		{releaseTag: "v1.2.0-rc.1", isValid: false},
+		{releaseTag: "v1.3.1", isValid: false},
+	}
+
+	for _, version := range versionsList {
+		unmarshaledData := &MixedJSONCase{}
+
+		err := (&Service{}).PrepareTo(unmarshaledData).PrepareFrom(rawData).
+			With(common.NewMockErrFormatter(), &mockReleaseVersionProvider{releaseTag: version.releaseTag}).
+			Do(context.Background())
+		if (err == nil) != version.isValid {
+			t.Errorf("wrong result of constraint check for %s", version.releaseTag)
+			continue
+		}
+
+		if version.isValid && (unmarshaledData.TopLevelField != 5 || len(unmarshaledData.List) != 2) {
+			t.Errorf("not equal unmarshaled data")
+		}
+	}
+
+	err := (&Service{}).PrepareTo(&MixedJSONCase{}).PrepareFrom(rawData).
+		With(common.NewMockErrFormatter()).
+		Do(context.Background())
+	if err == nil {
+		t.Errorf("expected error for constraint without release version provider")
+	}
+
+	err = NewService(nil).PrepareTo(&MixedJSONCase{}).PrepareFrom(rawData).Do(context.Background())
+	if !errors.Is(err, common.ErrReleaseVersionIsNotDefined) || !errors.Is(err, ErrReleaseVersionIsNotDefined) {
+		t.Errorf("expected release version is not defined error: %v", err)
+	}
+
+	err = NewService(nil).PrepareTo(&MixedJSONCase{}).
+		PrepareFrom([]byte(`{"list": [{"$min_version": 1.2, "int_field_one": 1}]}`)).
+		With(&mockReleaseVersionProvider{releaseTag: "v1.2.0"}).
+		Do(context.Background())
+	if !errors.Is(err, ErrWrongVersionConstraintType) || !strings.Contains(err.Error(), "/list/0/$min_version") {
+		t.Errorf("expected type error of version constraint: %v", err)
+	}
+}
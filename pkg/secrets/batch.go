@@ -0,0 +1,150 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// DefaultWorkersCount - default count of concurrent Get calls of GetManyConcurrently function...
+const DefaultWorkersCount = 8
+
+// KeyError - error of secret fetching, bound to secret key...
+type KeyError struct {
+	Cause error
+	Key   string
+}
+
+func (e *KeyError) Error() string {
+	return e.Key + ": " + e.Cause.Error()
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Cause
+}
+
+// GetManyConcurrently - implementation of GetMany function by concurrent Get calls with bounded count of workers.
+// Errors of all failed keys are aggregated by errors.Join as KeyError list in order of passed keys.
+// Absent secrets are not included in result map...
+func GetManyConcurrently(ctx context.Context,
+	provider SecretProvider,
+	keysList []string,
+	workersCount int,
+) (map[string]Secret, error) {
+	if workersCount <= 0 {
+		workersCount = DefaultWorkersCount
+	}
+
+	secretsList := make([]Secret, len(keysList))
+	errorsList := make([]error, len(keysList))
+	isExistsList := make([]bool, len(keysList))
+
+	indexesChan := make(chan int)
+	waitGroup := sync.WaitGroup{}
+
+	for range min(workersCount, len(keysList)) {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for i := range indexesChan {
+				secret, err := provider.Get(ctx, keysList[i])
+				switch {
+				case errors.Is(err, ErrSecretNotFound):
+					continue
+				case err != nil:
+					errorsList[i] = &KeyError{Cause: err, Key: keysList[i]}
+				default:
+					secretsList[i] = secret
+					isExistsList[i] = true
+				}
+			}
+		}()
+	}
+
+	for i := range keysList {
+		indexesChan <- i
+	}
+
+	close(indexesChan)
+	waitGroup.Wait()
+
+	result := make(map[string]Secret, len(keysList))
+
+	for i, key := range keysList {
+		if isExistsList[i] {
+			result[key] = secretsList[i]
+		}
+	}
+
+	err := errors.Join(errorsList...)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// SplitKeyErrors - split error of GetMany call to map of errors by secret keys and list of errors,
+// which are not bound to any secret key, e.g. failure of batch API call...
+func SplitKeyErrors(err error) (map[string]error, []error) {
+	keyErrorsMap := make(map[string]error)
+	otherErrorsList := make([]error, 0)
+
+	if err == nil {
+		return keyErrorsMap, otherErrorsList
+	}
+
+	errorsList := []error{err}
+
+	joinedErr, isJoined := err.(interface{ Unwrap() []error }) //nolint:errorlint // it's ok, check of joined error
+	if isJoined {
+		errorsList = joinedErr.Unwrap()
+	}
+
+	for _, itemErr := range errorsList {
+		var keyErr *KeyError
+		if errors.As(itemErr, &keyErr) {
+			keyErrorsMap[keyErr.Key] = keyErr.Cause
+
+			continue
+		}
+
+		otherErrorsList = append(otherErrorsList, itemErr)
+	}
+
+	return keyErrorsMap, otherErrorsList
+}
@@ -0,0 +1,122 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errStorageUnavailable = errors.New("storage unavailable")
+
+type mockConcurrentProvider struct {
+	activeCount atomic.Int32
+	maxCount    atomic.Int32
+	failedKeys  map[string]bool
+}
+
+func (m *mockConcurrentProvider) Get(_ context.Context, key string) (Secret, error) {
+	activeCount := m.activeCount.Add(1)
+	defer m.activeCount.Add(-1)
+
+	for {
+		maxCount := m.maxCount.Load()
+		if activeCount <= maxCount || m.maxCount.CompareAndSwap(maxCount, activeCount) {
+			break
+		}
+	}
+
+	time.Sleep(time.Millisecond * 10)
+
+	if m.failedKeys[key] {
+		return Secret{}, errStorageUnavailable
+	}
+
+	if key == "ABSENT" {
+		return Secret{}, ErrSecretNotFound
+	}
+
+	return Secret{Key: key, Value: "value_" + key}, nil
+}
+
+func (m *mockConcurrentProvider) GetMany(ctx context.Context, keysList []string) (map[string]Secret, error) {
+	return GetManyConcurrently(ctx, m, keysList, 4)
+}
+
+func TestGetManyConcurrently(t *testing.T) {
+	provider := &mockConcurrentProvider{
+		failedKeys: map[string]bool{"KEY_7": true, "KEY_2": true},
+	}
+
+	keysList := []string{"ABSENT"}
+	for i := range 16 {
+		keysList = append(keysList, "KEY_"+strconv.Itoa(i))
+	}
+
+	result, err := provider.GetMany(context.Background(), keysList)
+	if !errors.Is(err, errStorageUnavailable) {
+		t.Errorf("expected aggregated storage error: %v", err)
+		return
+	}
+
+	if err.Error() != "KEY_2: storage unavailable\nKEY_7: storage unavailable" {
+		t.Errorf("errors must be aggregated in order of keys: %s", err)
+	}
+
+	keyErrorsMap, otherErrorsList := SplitKeyErrors(err)
+	if len(keyErrorsMap) != 2 || len(otherErrorsList) != 0 || !errors.Is(keyErrorsMap["KEY_7"], errStorageUnavailable) {
+		t.Errorf("not equal split errors: %v, %v", keyErrorsMap, otherErrorsList)
+	}
+
+	if len(result) != 14 || result["KEY_15"].Value != "value_KEY_15" {
+		t.Errorf("not equal result: %+v", result)
+	}
+
+	if _, isExists := result["ABSENT"]; isExists {
+		t.Errorf("absent secret must not be included in result")
+	}
+
+	maxCount := provider.maxCount.Load()
+	if maxCount > 4 || maxCount < 2 {
+		t.Errorf("count of concurrent calls must be bounded by workers count: %d", maxCount)
+	}
+
+	_, otherErrorsList = SplitKeyErrors(errStorageUnavailable)
+	if len(otherErrorsList) != 1 {
+		t.Errorf("error without key must be returned as is")
+	}
+}
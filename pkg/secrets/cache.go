@@ -0,0 +1,364 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+var _ SecretProvider = (*cachingSecretManager)(nil)
+
+// CacheOptions - options of secrets caching decorator...
+type CacheOptions struct {
+	// Now - clock of cache, time.Now by default. Can be replaced in tests...
+	Now func() time.Time
+	// TTL - lifetime of cached secret value. Zero TTL - cached value never expires...
+	TTL time.Duration
+	// NegativeTTL - lifetime of cached absence of secret. Zero NegativeTTL - absence of secret is not cached...
+	NegativeTTL time.Duration
+	// RefreshInterval - interval of proactive background refresh of all cached secrets.
+	// Zero RefreshInterval - background refresh is disabled...
+	RefreshInterval time.Duration
+}
+
+// ChangeHook - function, which will be called on change of cached secret value, e.g. for re-prepare of config.
+// isExists is false if secret was removed from secrets storage...
+type ChangeHook func(keyName string, isExists bool)
+
+type cacheEntry struct {
+	expiresAt time.Time
+	secret    Secret
+	isExists  bool
+}
+
+// isFresh - check that entry is not expired. Entry with zero expiration time never expires...
+func (e *cacheEntry) isFresh(now time.Time) bool {
+	return e.expiresAt.IsZero() || now.Before(e.expiresAt)
+}
+
+// fetchCall - in-flight call of secrets storage, shared by concurrent requests of same secret...
+type fetchCall struct {
+	done   chan struct{}
+	err    error
+	secret Secret
+}
+
+// cachingSecretManager - caching decorator of secret provider with TTL, negative caching,
+// de-duplication of concurrent requests and background refresh.
+// Only ErrSecretNotFound error of secret provider removes cached value - on other errors, e.g. outage of
+// secrets storage, stale cached value is kept and returned...
+type cachingSecretManager struct {
+	mu sync.Mutex
+
+	sourceSvc SecretProvider
+	options   CacheOptions
+
+	entries map[string]*cacheEntry
+	calls   map[string]*fetchCall
+
+	hooksMu sync.RWMutex
+	hooks   []ChangeHook
+}
+
+// Get returns cached secret or fetches secret from secrets storage. Concurrent requests of same secret
+// share one call of secrets storage. Stale cached secret is returned, if secrets storage call failed...
+func (m *cachingSecretManager) Get(ctx context.Context, key string) (Secret, error) {
+	m.mu.Lock()
+
+	entry, isCached := m.entries[key]
+	if isCached && entry.isFresh(m.options.Now()) {
+		m.mu.Unlock()
+
+		if !entry.isExists {
+			return Secret{}, ErrSecretNotFound
+		}
+
+		return entry.secret, nil
+	}
+
+	m.mu.Unlock()
+
+	return m.fetch(ctx, key)
+}
+
+// GetMany returns cached secrets and fetches absent in cache secrets by concurrent Get calls.
+// Get calls are sequential, if source provider is adapted secret manager...
+func (m *cachingSecretManager) GetMany(ctx context.Context, keysList []string) (map[string]Secret, error) {
+	if isSequentialProvider(m.sourceSvc) {
+		return GetManyByOne(ctx, m, keysList)
+	}
+
+	return GetManyConcurrently(ctx, m, keysList, DefaultWorkersCount)
+}
+
+// GetByName - GetByName interface of secret manager, errors of secrets storage without cached value
+// are treated as absence of secret. Use Get function for error handling...
+func (m *cachingSecretManager) GetByName(keyName string) (string, bool) {
+	secret, err := m.Get(context.Background(), keyName)
+	if err != nil {
+		return "", false
+	}
+
+	return secret.Value, true
+}
+
+// GetSourceName returns name of decorated secret provider...
+func (m *cachingSecretManager) GetSourceName() string {
+	return "cache(" + common.ProviderName(m.sourceSvc) + ")"
+}
+
+// OnChange - register hook, which will be called on change of cached secret value...
+func (m *cachingSecretManager) OnChange(hook ChangeHook) {
+	m.hooksMu.Lock()
+	defer m.hooksMu.Unlock()
+
+	m.hooks = append(m.hooks, hook)
+}
+
+// Invalidate - remove cached values of passed secrets...
+func (m *cachingSecretManager) Invalidate(keyNamesList ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, keyName := range keyNamesList {
+		delete(m.entries, keyName)
+	}
+}
+
+// InvalidateAll - remove all cached values...
+func (m *cachingSecretManager) InvalidateAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = make(map[string]*cacheEntry)
+}
+
+// Refresh - fetch all cached secrets from secrets storage by one GetMany call. Change hooks will be called
+// for changed secrets. Secrets, which are absent in GetMany result, are checked by Get call - cached value
+// is removed only by ErrSecretNotFound error. Stale values of failed secrets are kept...
+func (m *cachingSecretManager) Refresh(ctx context.Context) {
+	m.mu.Lock()
+
+	keyNamesList := make([]string, 0, len(m.entries))
+	for keyName := range m.entries {
+		keyNamesList = append(keyNamesList, keyName)
+	}
+
+	m.mu.Unlock()
+
+	if len(keyNamesList) == 0 {
+		return
+	}
+
+	secretsMap, err := m.sourceSvc.GetMany(ctx, keyNamesList)
+
+	keyErrorsMap, otherErrorsList := SplitKeyErrors(err)
+	if len(otherErrorsList) != 0 {
+		// failure of secrets storage - stale values are kept
+		return
+	}
+
+	for _, keyName := range keyNamesList {
+		if ctx.Err() != nil {
+			return
+		}
+
+		secret, isExists := secretsMap[keyName]
+		if isExists {
+			_, _ = m.store(keyName, secret, nil)
+
+			continue
+		}
+
+		keyErr, isFailed := keyErrorsMap[keyName]
+		if isFailed {
+			// only not found error removes cached value, stale values of other errors are kept
+			_, _ = m.store(keyName, Secret{}, keyErr)
+
+			continue
+		}
+
+		// GetMany doesn't include absent secrets in result - absence must be confirmed by Get call
+		_, _ = m.fetch(ctx, keyName)
+	}
+}
+
+// Run - proactive background refresh of cached secrets with RefreshInterval. Function blocks until
+// context cancellation, must be called in separate goroutine...
+func (m *cachingSecretManager) Run(ctx context.Context) {
+	if m.options.RefreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.options.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Refresh(ctx)
+		}
+	}
+}
+
+func (m *cachingSecretManager) fetch(ctx context.Context, keyName string) (Secret, error) {
+	m.mu.Lock()
+
+	call, isInFlight := m.calls[keyName]
+	if isInFlight {
+		m.mu.Unlock()
+		<-call.done
+
+		return call.secret, call.err
+	}
+
+	call = &fetchCall{
+		done:   make(chan struct{}),
+		err:    nil,
+		secret: Secret{},
+	}
+	m.calls[keyName] = call
+
+	m.mu.Unlock()
+
+	secret, err := m.sourceSvc.Get(ctx, keyName)
+	call.secret, call.err = m.store(keyName, secret, err)
+
+	m.mu.Lock()
+	delete(m.calls, keyName)
+	m.mu.Unlock()
+
+	close(call.done)
+
+	return call.secret, call.err
+}
+
+// store - store result of secrets storage call in cache and notify change hooks. Returns cached secret,
+// if secrets storage call failed with error other than ErrSecretNotFound...
+func (m *cachingSecretManager) store(keyName string, secret Secret, err error) (Secret, error) {
+	isExists := err == nil
+	isNotFound := errors.Is(err, ErrSecretNotFound)
+
+	m.mu.Lock()
+
+	previousEntry, isCached := m.entries[keyName]
+
+	if err != nil && !isNotFound {
+		m.mu.Unlock()
+
+		if isCached && previousEntry.isExists {
+			return previousEntry.secret, nil
+		}
+
+		return Secret{}, err
+	}
+
+	isChanged := isCached &&
+		(previousEntry.isExists != isExists || previousEntry.secret.Value != secret.Value)
+
+	m.storeEntry(keyName, secret, isExists)
+
+	m.mu.Unlock()
+
+	if isChanged {
+		m.notify(keyName, isExists)
+	}
+
+	return secret, err
+}
+
+// storeEntry - store fetched secret in cache, must be called under lock...
+func (m *cachingSecretManager) storeEntry(keyName string, secret Secret, isExists bool) {
+	ttl := m.options.TTL
+	if !isExists {
+		ttl = m.options.NegativeTTL
+
+		if ttl <= 0 {
+			delete(m.entries, keyName)
+
+			return
+		}
+	}
+
+	entry := &cacheEntry{
+		expiresAt: time.Time{},
+		secret:    secret,
+		isExists:  isExists,
+	}
+
+	if ttl > 0 {
+		entry.expiresAt = m.options.Now().Add(ttl)
+	}
+
+	m.entries[keyName] = entry
+}
+
+func (m *cachingSecretManager) notify(keyName string, isExists bool) {
+	m.hooksMu.RLock()
+	hooksList := append([]ChangeHook(nil), m.hooks...)
+	m.hooksMu.RUnlock()
+
+	for _, hook := range hooksList {
+		hook(keyName, isExists)
+	}
+}
+
+// NewCachingSecretManager - create caching decorator of secret provider. Secret managers with GetByName function
+// can be adapted by AdaptSecretManager function, e.g.:
+//
+//	cachedSecrets := secrets.NewCachingSecretManager(vaultSecrets, secrets.CacheOptions{
+//		TTL: 5 * time.Minute, NegativeTTL: 30 * time.Second, RefreshInterval: time.Minute})
+//	cachedSecrets.OnChange(func(keyName string, isExists bool) { reloadCh <- struct{}{} })
+//	go cachedSecrets.Run(ctx)
+func NewCachingSecretManager(sourceSvc SecretProvider, options CacheOptions) *cachingSecretManager {
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	return &cachingSecretManager{
+		mu:        sync.Mutex{},
+		sourceSvc: sourceSvc,
+		options:   options,
+		entries:   make(map[string]*cacheEntry),
+		calls:     make(map[string]*fetchCall),
+		hooksMu:   sync.RWMutex{},
+		hooks:     make([]ChangeHook, 0),
+	}
+}
@@ -0,0 +1,310 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errStorageIsUnavailable = errors.New("secrets storage is unavailable")
+
+type mockSecretManager struct {
+	mu         sync.Mutex
+	callsCount atomic.Int32
+	ValuesPool map[string]string
+}
+
+func (m *mockSecretManager) GetByName(keyName string) (string, bool) {
+	m.callsCount.Add(1)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result, isExists := m.ValuesPool[keyName]
+
+	return result, isExists
+}
+
+func (m *mockSecretManager) setValue(keyName, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ValuesPool[keyName] = value
+}
+
+func (m *mockSecretManager) deleteValue(keyName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.ValuesPool, keyName)
+}
+
+// mockUnstableProvider - secret provider, which can emulate outage of secrets storage...
+type mockUnstableProvider struct {
+	mockSecretManager
+
+	gate        chan struct{}
+	isAvailable atomic.Bool
+}
+
+func (p *mockUnstableProvider) Get(ctx context.Context, key string) (Secret, error) {
+	if p.gate != nil {
+		<-p.gate
+	}
+
+	if !p.isAvailable.Load() {
+		p.callsCount.Add(1)
+
+		return Secret{}, errStorageIsUnavailable
+	}
+
+	return (&secretManagerAdapter{secretManagerSvc: &p.mockSecretManager}).Get(ctx, key)
+}
+
+func (p *mockUnstableProvider) GetMany(ctx context.Context, keysList []string) (map[string]Secret, error) {
+	return GetManyConcurrently(ctx, p, keysList, DefaultWorkersCount)
+}
+
+func newMockUnstableProvider(valuesPool map[string]string) *mockUnstableProvider {
+	provider := &mockUnstableProvider{
+		mockSecretManager: mockSecretManager{ValuesPool: valuesPool},
+		gate:              nil,
+	}
+	provider.isAvailable.Store(true)
+
+	return provider
+}
+
+// mockClock - manually advanced clock of cache...
+type mockClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *mockClock) Advance(duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(duration)
+}
+
+func TestCachingSecretManagerDeduplication(t *testing.T) {
+	sourceSvc := newMockUnstableProvider(map[string]string{"DATABASE_PASSWORD": "password"})
+	sourceSvc.gate = make(chan struct{})
+
+	cachedSvc := NewCachingSecretManager(sourceSvc, CacheOptions{
+		Now:             (&mockClock{now: time.Unix(0, 0)}).Now,
+		TTL:             time.Minute,
+		NegativeTTL:     time.Minute,
+		RefreshInterval: 0,
+	})
+
+	waitGroup := sync.WaitGroup{}
+	for range 10 {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			value, isExists := cachedSvc.GetByName("DATABASE_PASSWORD")
+			if !isExists || value != "password" {
+				t.Errorf("not equal secret value")
+			}
+		}()
+	}
+
+	close(sourceSvc.gate)
+	waitGroup.Wait()
+
+	_, isExists := cachedSvc.GetByName("UNKNOWN_SECRET")
+	_, isExistsAgain := cachedSvc.GetByName("UNKNOWN_SECRET")
+
+	if isExists || isExistsAgain {
+		t.Errorf("unknown secret must be absent")
+	}
+
+	if sourceSvc.callsCount.Load() != 2 {
+		t.Errorf("expected 2 calls of secrets storage, got %d", sourceSvc.callsCount.Load())
+	}
+}
+
+func TestCachingSecretManagerTTLAndHooks(t *testing.T) {
+	sourceSvc := newMockUnstableProvider(map[string]string{"API_TOKEN": "token_v1"})
+	clock := &mockClock{now: time.Unix(0, 0)}
+
+	cachedSvc := NewCachingSecretManager(sourceSvc, CacheOptions{
+		Now:             clock.Now,
+		TTL:             50 * time.Millisecond,
+		NegativeTTL:     0,
+		RefreshInterval: 0,
+	})
+
+	changedKeysCh := make(chan string, 1)
+	cachedSvc.OnChange(func(keyName string, _ bool) {
+		changedKeysCh <- keyName
+	})
+
+	value, _ := cachedSvc.GetByName("API_TOKEN")
+	sourceSvc.setValue("API_TOKEN", "token_v2")
+
+	clock.Advance(49 * time.Millisecond)
+
+	cachedValue, _ := cachedSvc.GetByName("API_TOKEN")
+	if value != "token_v1" || cachedValue != "token_v1" {
+		t.Errorf("cached value must be returned before TTL expiration")
+	}
+
+	clock.Advance(time.Millisecond)
+
+	value, _ = cachedSvc.GetByName("API_TOKEN")
+	if value != "token_v2" {
+		t.Errorf("value must be fetched after TTL expiration")
+	}
+
+	select {
+	case keyName := <-changedKeysCh:
+		if keyName != "API_TOKEN" {
+			t.Errorf("not equal changed key name")
+		}
+	default:
+		t.Errorf("change hook not called")
+	}
+
+	cachedSvc.GetByName("UNKNOWN_SECRET")
+	cachedSvc.GetByName("UNKNOWN_SECRET")
+
+	if sourceSvc.callsCount.Load() != 4 {
+		t.Errorf("absence of secret must not be cached without NegativeTTL")
+	}
+}
+
+func TestCachingSecretManagerOutage(t *testing.T) {
+	sourceSvc := newMockUnstableProvider(map[string]string{"API_TOKEN": "token_v1", "DB_PASSWORD": "password"})
+	clock := &mockClock{now: time.Unix(0, 0)}
+
+	cachedSvc := NewCachingSecretManager(sourceSvc, CacheOptions{
+		Now:             clock.Now,
+		TTL:             time.Minute,
+		NegativeTTL:     0,
+		RefreshInterval: 0,
+	})
+
+	hookCallsCount := atomic.Int32{}
+	cachedSvc.OnChange(func(_ string, _ bool) {
+		hookCallsCount.Add(1)
+	})
+
+	_, err := cachedSvc.GetMany(context.Background(), []string{"API_TOKEN", "DB_PASSWORD"})
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	sourceSvc.isAvailable.Store(false)
+	clock.Advance(2 * time.Minute)
+
+	cachedSvc.Refresh(context.Background())
+
+	secret, err := cachedSvc.Get(context.Background(), "API_TOKEN")
+	if err != nil || secret.Value != "token_v1" {
+		t.Errorf("stale value must be kept during outage of secrets storage: %v", err)
+	}
+
+	_, err = cachedSvc.Get(context.Background(), "UNKNOWN_SECRET")
+	if !errors.Is(err, errStorageIsUnavailable) {
+		t.Errorf("expected error of secrets storage for not cached secret: %v", err)
+	}
+
+	if hookCallsCount.Load() != 0 {
+		t.Errorf("change hooks must not be called during outage of secrets storage")
+	}
+
+	sourceSvc.isAvailable.Store(true)
+	sourceSvc.deleteValue("DB_PASSWORD")
+
+	cachedSvc.Refresh(context.Background())
+
+	_, err = cachedSvc.Get(context.Background(), "DB_PASSWORD")
+	if !errors.Is(err, ErrSecretNotFound) || hookCallsCount.Load() != 1 {
+		t.Errorf("removed secret must be evicted by not found error: %v", err)
+	}
+}
+
+func TestCachingSecretManagerBackgroundRefresh(t *testing.T) {
+	sourceSvc := newMockUnstableProvider(map[string]string{"API_TOKEN": "token_v1"})
+
+	cachedSvc := NewCachingSecretManager(sourceSvc, CacheOptions{
+		Now:             nil,
+		TTL:             0,
+		NegativeTTL:     0,
+		RefreshInterval: time.Millisecond,
+	})
+
+	changedKeysCh := make(chan string, 1)
+	cachedSvc.OnChange(func(keyName string, _ bool) {
+		changedKeysCh <- keyName
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cachedSvc.GetByName("API_TOKEN")
+
+	go cachedSvc.Run(ctx)
+
+	sourceSvc.setValue("API_TOKEN", "token_v2")
+
+	select {
+	case <-changedKeysCh:
+	case <-time.After(5 * time.Second):
+		t.Errorf("background refresh not detected secret change")
+		return
+	}
+
+	value, _ := cachedSvc.GetByName("API_TOKEN")
+	if value != "token_v2" {
+		t.Errorf("refreshed value must be returned from cache")
+	}
+}
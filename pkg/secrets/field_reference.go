@@ -0,0 +1,88 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+// FieldReference - secret reference of config field, found by discovery pass of config filler...
+type FieldReference struct {
+	// Field - description of secret field, used for errors of failed secrets...
+	Field     common.Field
+	Reference Reference
+	// Path - path of config field, e.g. AppConfig.Database.Password...
+	Path string
+}
+
+// ResolveFieldReferences - fetch secrets of config fields by one GetReferences call, before assignment of values.
+// Result map uses keys of references, absent secrets are not included in result map.
+// Errors of failed fields are aggregated as common.FieldError list in order of passed references,
+// errors, which are not bound to any secret key, are appended after them...
+func ResolveFieldReferences(ctx context.Context,
+	provider SecretProvider,
+	refsList []FieldReference,
+) (map[string]Secret, error) {
+	if provider == nil || len(refsList) == 0 {
+		return make(map[string]Secret), nil
+	}
+
+	referencesList := make([]Reference, len(refsList))
+	for i, ref := range refsList {
+		referencesList[i] = ref.Reference
+	}
+
+	resolvedSecretsMap, err := GetReferences(ctx, provider, referencesList)
+	if err == nil {
+		return resolvedSecretsMap, nil
+	}
+
+	keyErrorsMap, errorsList := SplitKeyErrors(err)
+
+	fieldErrorsList := make([]error, 0, len(keyErrorsMap)+len(errorsList))
+	for _, ref := range refsList {
+		keyErr, isFailed := keyErrorsMap[ref.Reference.Key()]
+		if !isFailed {
+			continue
+		}
+
+		fieldErrorsList = append(fieldErrorsList, common.NewFieldError(keyErr, ref.Path, ref.Field, true))
+	}
+
+	fieldErrorsList = append(fieldErrorsList, errorsList...)
+
+	return nil, errors.Join(fieldErrorsList...)
+}
@@ -0,0 +1,102 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+func TestResolveFieldReferences(t *testing.T) {
+	newFieldReference := func(rawReference, path string) FieldReference {
+		reference, err := ParseReference(rawReference)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+
+		return FieldReference{
+			Field: common.Field{
+				Name:      path,
+				EnvKey:    reference.Key(),
+				SecretKey: reference.Key(),
+				Source:    "secret",
+			},
+			Reference: reference,
+			Path:      path,
+		}
+	}
+
+	refsList := []FieldReference{
+		newFieldReference("DB_PASSWORD", "Config.DB.Password"),
+		newFieldReference("API_TOKEN|fallback", "Config.API.Token"),
+	}
+
+	provider := newMockUnstableProvider(map[string]string{
+		"DB_PASSWORD": "password",
+	})
+
+	resolvedSecretsMap, err := ResolveFieldReferences(context.Background(), provider, refsList)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if resolvedSecretsMap["DB_PASSWORD"].Value != "password" || len(resolvedSecretsMap) != 1 {
+		t.Errorf("not equal resolved secrets: %+v", resolvedSecretsMap)
+	}
+
+	secret, isExists := refsList[1].Reference.Lookup(resolvedSecretsMap)
+	if !isExists || secret.Value != "fallback" {
+		t.Errorf("default value of absent secret must be used: %+v", secret)
+	}
+
+	provider.isAvailable.Store(false)
+
+	_, err = ResolveFieldReferences(context.Background(), provider, refsList)
+	if !errors.Is(err, errStorageIsUnavailable) {
+		t.Errorf("expected storage error: %v", err)
+	}
+
+	var fieldErr *common.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Path != "Config.DB.Password" {
+		t.Errorf("expected field error of first reference: %v", err)
+	}
+
+	resolvedSecretsMap, err = ResolveFieldReferences(context.Background(), nil, refsList)
+	if err != nil || len(resolvedSecretsMap) != 0 {
+		t.Errorf("nil provider must return empty result: %v, %+v", err, resolvedSecretsMap)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/common"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Secret - secret value with metadata...
+type Secret struct {
+	// ExpiresAt - expiration time of secret lease, zero time if secret has no lease...
+	ExpiresAt time.Time
+	// Metadata - provider-specific metadata of secret, e.g. path, mount, created time...
+	Metadata map[string]string
+	Key      string
+	Value    string
+	// Version - version of secret in secrets storage, empty if storage doesn't support versions...
+	Version string
+	// LeaseID - identifier of secret lease, empty if secret has no lease...
+	LeaseID string
+}
+
+// HasLease - check that secret has lease with expiration time...
+func (s *Secret) HasLease() bool {
+	return !s.ExpiresAt.IsZero()
+}
+
+// SecretProvider - context-aware provider of secrets. Get must return error wrapping ErrSecretNotFound
+// if secret is absent, any other error means failure of secrets storage...
+type SecretProvider interface {
+	Get(ctx context.Context, key string) (Secret, error)
+	// GetMany returns map of found secrets, absent secrets are not included in result map...
+	GetMany(ctx context.Context, keysList []string) (map[string]Secret, error)
+}
+
+// secretManagerAdapter - adapter of GetByName secret manager to SecretProvider interface...
+type secretManagerAdapter struct {
+	secretManagerSvc secretManagerService
+}
+
+func (a *secretManagerAdapter) Get(ctx context.Context, key string) (Secret, error) {
+	err := ctx.Err()
+	if err != nil {
+		return Secret{}, err //nolint:wrapcheck // it's ok, context error
+	}
+
+	value, isExists := a.secretManagerSvc.GetByName(key)
+	if !isExists {
+		return Secret{}, ErrSecretNotFound
+	}
+
+	return Secret{
+		ExpiresAt: time.Time{},
+		Metadata:  nil,
+		Key:       key,
+		Value:     value,
+		Version:   "",
+		LeaseID:   "",
+	}, nil
+}
+
+// GetMany - sequential Get calls, GetByName function of adapted secret manager isn't required
+// to be safe for concurrent use...
+func (a *secretManagerAdapter) GetMany(ctx context.Context, keysList []string) (map[string]Secret, error) {
+	return GetManyByOne(ctx, a, keysList)
+}
+
+// GetByName - GetByName interface of adapted secret manager...
+func (a *secretManagerAdapter) GetByName(keyName string) (string, bool) {
+	return a.secretManagerSvc.GetByName(keyName)
+}
+
+// GetSourceName returns name of adapted secret manager...
+func (a *secretManagerAdapter) GetSourceName() string {
+	return common.ProviderName(a.secretManagerSvc)
+}
+
+// GetManyByOne - implementation of GetMany function by sequential Get calls. Stops on first failed key,
+// error returned as KeyError. Can be used by providers without batch API...
+func GetManyByOne(ctx context.Context, provider SecretProvider, keysList []string) (map[string]Secret, error) {
+	result := make(map[string]Secret, len(keysList))
+
+	for _, key := range keysList {
+		secret, err := provider.Get(ctx, key)
+		if errors.Is(err, ErrSecretNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, &KeyError{Cause: err, Key: key}
+		}
+
+		result[key] = secret
+	}
+
+	return result, nil
+}
+
+// AdaptSecretManager - adapt secret manager with GetByName function to SecretProvider interface.
+// GetByName function is never called concurrently by adapter, caching secret manager and scheme router
+// with adapted secret manager...
+func AdaptSecretManager(secretManagerSvc secretManagerService) SecretProvider {
+	return &secretManagerAdapter{
+		secretManagerSvc: secretManagerSvc,
+	}
+}
+
+// isSequentialProvider - check that Get function of provider calls adapted secret manager, which GetByName
+// function isn't required to be safe for concurrent use...
+func isSequentialProvider(provider SecretProvider) bool {
+	switch castedProvider := provider.(type) {
+	case *secretManagerAdapter:
+		return true
+	case *cachingSecretManager:
+		return isSequentialProvider(castedProvider.sourceSvc)
+	case *schemeRouter:
+		if isSequentialProvider(castedProvider.defaultProvider) {
+			return true
+		}
+
+		for _, routedProvider := range castedProvider.providersMap {
+			if isSequentialProvider(routedProvider) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
+
+// ToSecretProvider - cast passed service to SecretProvider. Secret managers with GetByName function
+// will be adapted automatically. Returns false, if service is not a secret provider or secret manager...
+func ToSecretProvider(svc interface{}) (SecretProvider, bool) {
+	switch castedSvc := svc.(type) {
+	case SecretProvider:
+		return castedSvc, true
+	case secretManagerService:
+		return AdaptSecretManager(castedSvc), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockSequentialSecretManager - secret manager, which GetByName function isn't safe for concurrent use...
+type mockSequentialSecretManager struct {
+	inFlightCount atomic.Int32
+	maxCount      atomic.Int32
+}
+
+func (m *mockSequentialSecretManager) GetByName(keyName string) (string, bool) {
+	count := m.inFlightCount.Add(1)
+	defer m.inFlightCount.Add(-1)
+
+	if count > m.maxCount.Load() {
+		m.maxCount.Store(count)
+	}
+
+	time.Sleep(time.Millisecond)
+
+	return keyName, true
+}
+
+func TestAdaptSecretManager(t *testing.T) {
+	sourceSvc := &mockSecretManager{
+		ValuesPool: map[string]string{
+			"DB_PASSWORD": "password",
+			"API_TOKEN":   "token",
+		},
+	}
+
+	provider, isProvider := ToSecretProvider(sourceSvc)
+	if !isProvider {
+		t.Errorf("secret manager must be adapted to secret provider")
+		return
+	}
+
+	secret, err := provider.Get(context.Background(), "DB_PASSWORD")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if secret.Key != "DB_PASSWORD" || secret.Value != "password" || secret.HasLease() {
+		t.Errorf("not equal secret: %+v", secret)
+	}
+
+	_, err = provider.Get(context.Background(), "UNKNOWN")
+	if !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("expected not found error: %v", err)
+	}
+
+	secretsList, err := provider.GetMany(context.Background(), []string{"DB_PASSWORD", "UNKNOWN", "API_TOKEN"})
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if len(secretsList) != 2 || secretsList["API_TOKEN"].Value != "token" {
+		t.Errorf("not equal secrets list: %+v", secretsList)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = provider.Get(ctx, "DB_PASSWORD")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled error: %v", err)
+	}
+
+	if sourceSvc.callsCount.Load() != 5 {
+		t.Errorf("secret manager must not be called with canceled context: %d", sourceSvc.callsCount.Load())
+	}
+
+	_, isProvider = ToSecretProvider("not a provider")
+	if isProvider {
+		t.Errorf("string must not be casted to secret provider")
+	}
+}
+
+func TestAdaptSecretManagerSequentialCalls(t *testing.T) {
+	keysList := make([]string, 0, 16)
+	for i := range 16 {
+		keysList = append(keysList, "KEY_"+strconv.Itoa(i))
+	}
+
+	sourceSvc := &mockSequentialSecretManager{}
+	adaptedSvc := AdaptSecretManager(sourceSvc)
+
+	providersList := []SecretProvider{
+		adaptedSvc,
+		NewCachingSecretManager(adaptedSvc, CacheOptions{}),
+		NewSchemeRouter(adaptedSvc, nil),
+	}
+
+	for _, provider := range providersList {
+		secretsList, err := provider.GetMany(context.Background(), keysList)
+		if err != nil || len(secretsList) != len(keysList) {
+			t.Errorf("not equal secrets list: %d, %v", len(secretsList), err)
+		}
+	}
+
+	if sourceSvc.maxCount.Load() != 1 {
+		t.Errorf("adapted secret manager must not be called concurrently: %d", sourceSvc.maxCount.Load())
+	}
+}
@@ -0,0 +1,236 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
+)
+
+var ErrWrongSecretReference = errors.New("wrong secret reference")
+
+const (
+	// ReferencePrefix - prefix of config values, which must be replaced by secret value, e.g. "!secret:DB_PASSWORD"...
+	ReferencePrefix = "!secret:"
+
+	referenceSchemeSeparator  = ":"
+	referenceFieldSeparator   = "#"
+	referenceVersionSeparator = "@"
+	referenceDefaultSeparator = "|"
+)
+
+// Reference - parsed secret reference in format [scheme:]path[#field][@version][|default], e.g.:
+//   - DB_PASSWORD - secret by name, all parts except path are optional
+//   - vault:wallet/hot#password@v3 - field "password" of third version of wallet/hot secret of vault provider
+//   - vault:wallet/hot#password|changeme - same field of latest version, "changeme" used if secret is absent
+//
+// Scheme selects secret provider of scheme router, default value is used only for absent secrets -
+// failures of secrets storage are still reported as errors...
+type Reference struct {
+	Scheme  string
+	Path    string
+	Field   string
+	Version string
+	Default string
+	// HasDefault - reference has default value, default value can be empty string...
+	HasDefault bool
+}
+
+// ParseReference - parse secret reference in format [scheme:]path[#field][@version][|default].
+// Scheme must start with lowercase latin letter and can contain lowercase latin letters, digits, "+", "-" and ".".
+// Default value is a tail of reference after first "|" symbol and can contain any symbols...
+func ParseReference(rawReference string) (Reference, error) {
+	reference := Reference{
+		Scheme:     "",
+		Path:       "",
+		Field:      "",
+		Version:    "",
+		Default:    "",
+		HasDefault: false,
+	}
+
+	rawLocation, defaultValue, hasDefault := strings.Cut(rawReference, referenceDefaultSeparator)
+	if hasDefault {
+		reference.Default, reference.HasDefault = defaultValue, true
+	}
+
+	scheme, rawPath, hasScheme := strings.Cut(rawLocation, referenceSchemeSeparator)
+	if hasScheme && isReferenceScheme(scheme) {
+		reference.Scheme, rawLocation = scheme, rawPath
+	}
+
+	versionIndex := strings.LastIndex(rawLocation, referenceVersionSeparator)
+	if versionIndex >= 0 {
+		reference.Version, rawLocation = rawLocation[versionIndex+1:], rawLocation[:versionIndex]
+		if reference.Version == "" {
+			return Reference{}, errfmt.ErrorOnly(ErrWrongSecretReference, "empty version", rawReference)
+		}
+	}
+
+	rawLocation, field, hasField := strings.Cut(rawLocation, referenceFieldSeparator)
+	if hasField {
+		if field == "" {
+			return Reference{}, errfmt.ErrorOnly(ErrWrongSecretReference, "empty field", rawReference)
+		}
+
+		reference.Field = field
+	}
+
+	if rawLocation == "" {
+		return Reference{}, errfmt.ErrorOnly(ErrWrongSecretReference, "empty path", rawReference)
+	}
+
+	reference.Path = rawLocation
+
+	return reference, nil
+}
+
+// Key returns reference without default value - [scheme:]path[#field][@version].
+// Key used as secret key of providers without GetByReference function and as key of resolved secrets map...
+func (r Reference) Key() string {
+	var builder strings.Builder
+
+	if r.Scheme != "" {
+		builder.WriteString(r.Scheme + referenceSchemeSeparator)
+	}
+
+	builder.WriteString(r.Path)
+
+	if r.Field != "" {
+		builder.WriteString(referenceFieldSeparator + r.Field)
+	}
+
+	if r.Version != "" {
+		builder.WriteString(referenceVersionSeparator + r.Version)
+	}
+
+	return builder.String()
+}
+
+// String returns reference in format [scheme:]path[#field][@version][|default]...
+func (r Reference) String() string {
+	if !r.HasDefault {
+		return r.Key()
+	}
+
+	return r.Key() + referenceDefaultSeparator + r.Default
+}
+
+// Lookup - search secret of reference in map of resolved secrets. Default value returned for absent secret,
+// if reference has default value...
+func (r Reference) Lookup(secretsMap map[string]Secret) (Secret, bool) {
+	secret, isExists := secretsMap[r.Key()]
+	if isExists || !r.HasDefault {
+		return secret, isExists
+	}
+
+	return Secret{
+		ExpiresAt: time.Time{},
+		Metadata:  nil,
+		Key:       r.Key(),
+		Value:     r.Default,
+		Version:   "",
+		LeaseID:   "",
+	}, true
+}
+
+// ReferenceProvider - optional interface of secret provider, which can use parsed secret reference
+// instead of secret key...
+type ReferenceProvider interface {
+	GetByReference(ctx context.Context, reference Reference) (Secret, error)
+}
+
+// referenceGetter - adapter of ReferenceProvider to Get function of SecretProvider for concurrent fetching...
+type referenceGetter struct {
+	provider      ReferenceProvider
+	referencesMap map[string]Reference
+}
+
+func (g *referenceGetter) Get(ctx context.Context, key string) (Secret, error) {
+	return g.provider.GetByReference(ctx, g.referencesMap[key])
+}
+
+func (g *referenceGetter) GetMany(ctx context.Context, keysList []string) (map[string]Secret, error) {
+	return GetManyConcurrently(ctx, g, keysList, DefaultWorkersCount)
+}
+
+// GetReferences - fetch secrets of references. Providers with GetByReference function receive parsed references,
+// other providers - keys of references by one GetMany call. Result map and KeyError list use keys of references.
+// Absent secrets are not included in result map - use Lookup function of reference for default values...
+func GetReferences(ctx context.Context,
+	provider SecretProvider,
+	referencesList []Reference,
+) (map[string]Secret, error) {
+	keysList := make([]string, 0, len(referencesList))
+	referencesMap := make(map[string]Reference, len(referencesList))
+
+	for _, reference := range referencesList {
+		key := reference.Key()
+		if _, isAdded := referencesMap[key]; isAdded {
+			continue
+		}
+
+		referencesMap[key] = reference
+		keysList = append(keysList, key)
+	}
+
+	referenceProvider, isReferenceProvider := provider.(ReferenceProvider)
+	if !isReferenceProvider {
+		return provider.GetMany(ctx, keysList) //nolint:wrapcheck // it's ok, errors of provider are KeyError list
+	}
+
+	return GetManyConcurrently(ctx, &referenceGetter{
+		provider:      referenceProvider,
+		referencesMap: referencesMap,
+	}, keysList, DefaultWorkersCount)
+}
+
+func isReferenceScheme(value string) bool {
+	if value == "" || value[0] < 'a' || value[0] > 'z' {
+		return false
+	}
+
+	for _, symbol := range value {
+		isAllowed := (symbol >= 'a' && symbol <= 'z') || (symbol >= '0' && symbol <= '9') ||
+			symbol == '+' || symbol == '-' || symbol == '.'
+		if !isAllowed {
+			return false
+		}
+	}
+
+	return true
+}
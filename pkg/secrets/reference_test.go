@@ -0,0 +1,159 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	casesList := []struct {
+		rawReference string
+		expected     Reference
+	}{
+		{"DB_PASSWORD", Reference{Scheme: "", Path: "DB_PASSWORD", Field: "", Version: "", Default: "", HasDefault: false}},
+		{"vault:wallet/hot#password@v3", Reference{
+			Scheme: "vault", Path: "wallet/hot", Field: "password", Version: "v3", Default: "", HasDefault: false,
+		}},
+		{"vault:wallet/hot#password|a|b:c", Reference{
+			Scheme: "vault", Path: "wallet/hot", Field: "password", Version: "", Default: "a|b:c", HasDefault: true,
+		}},
+		{"API_KEY|", Reference{Scheme: "", Path: "API_KEY", Field: "", Version: "", Default: "", HasDefault: true}},
+		{"Host:8080@2", Reference{Scheme: "", Path: "Host:8080", Field: "", Version: "2", Default: "", HasDefault: false}},
+	}
+
+	for _, testCase := range casesList {
+		reference, err := ParseReference(testCase.rawReference)
+		if err != nil {
+			t.Errorf("%s: %s", testCase.rawReference, err)
+			continue
+		}
+
+		if reference != testCase.expected {
+			t.Errorf("%s: not equal reference: %+v", testCase.rawReference, reference)
+		}
+
+		if reference.String() != testCase.rawReference {
+			t.Errorf("%s: not equal string of reference: %s", testCase.rawReference, reference.String())
+		}
+	}
+
+	for _, rawReference := range []string{"", "vault:", "path#", "path@", "#field", "vault:@1|default"} {
+		_, err := ParseReference(rawReference)
+		if !errors.Is(err, ErrWrongSecretReference) {
+			t.Errorf("%s: expected wrong reference error: %v", rawReference, err)
+		}
+	}
+}
+
+type mockReferenceProvider struct {
+	mockSecretManager
+
+	referencesList []Reference
+	mu             sync.Mutex
+}
+
+func (p *mockReferenceProvider) Get(_ context.Context, key string) (Secret, error) {
+	return Secret{}, errors.New("Get function must not be called: " + key) //nolint:err113 // it's ok, test error
+}
+
+func (p *mockReferenceProvider) GetMany(ctx context.Context, keysList []string) (map[string]Secret, error) {
+	return GetManyByOne(ctx, p, keysList)
+}
+
+func (p *mockReferenceProvider) GetByReference(_ context.Context, reference Reference) (Secret, error) {
+	p.mu.Lock()
+	p.referencesList = append(p.referencesList, reference)
+	p.mu.Unlock()
+
+	value, isExists := p.GetByName(reference.Path + "/" + reference.Field)
+	if !isExists {
+		return Secret{}, ErrSecretNotFound
+	}
+
+	return Secret{Key: reference.Key(), Value: value}, nil
+}
+
+func TestGetReferencesAndSchemeRouter(t *testing.T) {
+	vaultProvider := &mockReferenceProvider{
+		mockSecretManager: mockSecretManager{ValuesPool: map[string]string{"wallet/hot/password": "hot"}},
+		referencesList:    nil,
+		mu:                sync.Mutex{},
+	}
+	envProvider := AdaptSecretManager(&mockSecretManager{ValuesPool: map[string]string{"API_KEY": "key"}})
+
+	router := NewSchemeRouter(envProvider, map[string]SecretProvider{"vault": vaultProvider})
+
+	referencesList := make([]Reference, 0)
+
+	for _, rawReference := range []string{
+		"vault:wallet/hot#password@v3", "API_KEY", "vault:wallet/hot#password@v3|unused",
+		"vault:wallet/cold#password|fallback", "aws:db",
+	} {
+		reference, err := ParseReference(rawReference)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+
+		referencesList = append(referencesList, reference)
+	}
+
+	secretsMap, err := GetReferences(context.Background(), router, referencesList)
+
+	keyErrorsMap, otherErrorsList := SplitKeyErrors(err)
+	if len(keyErrorsMap) != 1 || len(otherErrorsList) != 0 || !errors.Is(keyErrorsMap["aws:db"], ErrUnknownSecretScheme) {
+		t.Errorf("expected unknown scheme error: %v", err)
+	}
+
+	if len(secretsMap) != 2 || secretsMap["vault:wallet/hot#password@v3"].Value != "hot" ||
+		secretsMap["API_KEY"].Value != "key" || len(vaultProvider.referencesList) != 2 {
+		t.Errorf("not equal resolved secrets: %+v", secretsMap)
+	}
+
+	if vaultProvider.referencesList[0].Scheme != "" {
+		t.Errorf("scheme must be removed from routed reference: %+v", vaultProvider.referencesList[0])
+	}
+
+	secret, isExists := referencesList[3].Lookup(secretsMap)
+	if !isExists || secret.Value != "fallback" || secret.Key != "vault:wallet/cold#password" {
+		t.Errorf("expected default value of absent secret: %+v", secret)
+	}
+
+	_, isExists = referencesList[1].Lookup(map[string]Secret{})
+	if isExists {
+		t.Errorf("secret without default value must be absent")
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package secrets
+
+import (
+	"context"
+	"errors"
+
+	errfmt "github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/errors"
+)
+
+var ErrUnknownSecretScheme = errors.New("unknown scheme of secret reference")
+
+// schemeRouter - secret provider, which routes secret references to providers by scheme of reference.
+// References without scheme are routed to default provider. Scheme is removed from reference before routing...
+type schemeRouter struct {
+	defaultProvider SecretProvider
+	providersMap    map[string]SecretProvider
+}
+
+// Get - parse key as secret reference and fetch secret by provider of reference scheme...
+func (r *schemeRouter) Get(ctx context.Context, key string) (Secret, error) {
+	reference, err := ParseReference(key)
+	if err != nil {
+		return Secret{}, err
+	}
+
+	return r.GetByReference(ctx, reference)
+}
+
+// GetMany - concurrent Get calls, or sequential calls if any of routed providers is adapted secret manager...
+func (r *schemeRouter) GetMany(ctx context.Context, keysList []string) (map[string]Secret, error) {
+	if isSequentialProvider(r) {
+		return GetManyByOne(ctx, r, keysList)
+	}
+
+	return GetManyConcurrently(ctx, r, keysList, DefaultWorkersCount)
+}
+
+// GetByReference - fetch secret by provider of reference scheme. Key of returned secret is a key of passed reference...
+func (r *schemeRouter) GetByReference(ctx context.Context, reference Reference) (Secret, error) {
+	provider := r.defaultProvider
+	if reference.Scheme != "" {
+		provider = r.providersMap[reference.Scheme]
+	}
+
+	if provider == nil {
+		return Secret{}, errfmt.ErrorOnly(ErrUnknownSecretScheme, reference.Scheme)
+	}
+
+	routedReference := reference
+	routedReference.Scheme = ""
+
+	var (
+		secret Secret
+		err    error
+	)
+
+	referenceProvider, isReferenceProvider := provider.(ReferenceProvider)
+	if isReferenceProvider {
+		secret, err = referenceProvider.GetByReference(ctx, routedReference)
+	} else {
+		secret, err = provider.Get(ctx, routedReference.Key())
+	}
+
+	if err != nil {
+		return Secret{}, err //nolint:wrapcheck // it's ok, error of routed provider
+	}
+
+	secret.Key = reference.Key()
+
+	return secret, nil
+}
+
+// NewSchemeRouter - create secret provider, which routes references by scheme, e.g. "vault:wallet/hot#password"
+// to provider of "vault" key of providersMap. defaultProvider is optional - references without scheme
+// are reported as ErrUnknownSecretScheme errors if nil passed...
+func NewSchemeRouter(defaultProvider SecretProvider, providersMap map[string]SecretProvider) *schemeRouter {
+	return &schemeRouter{
+		defaultProvider: defaultProvider,
+		providersMap:    providersMap,
+	}
+}
@@ -0,0 +1,291 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrAuthMethodIsNotDefined = errors.New("vault auth method is not defined: token or AppRole must be passed")
+	ErrEmptyAuthResponse      = errors.New("vault auth response doesn't contain client token")
+)
+
+const (
+	defaultAppRoleMount = "approle"
+
+	// minRenewDelay - minimal delay between token renewal attempts...
+	minRenewDelay = time.Second
+)
+
+// AppRoleAuth - credentials of AppRole auth method...
+type AppRoleAuth struct {
+	RoleID   string
+	SecretID string
+	// Mount - path of AppRole auth method, "approle" by default...
+	Mount string
+}
+
+type authResponse struct {
+	Auth *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int64  `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+type tokenLookupResponse struct {
+	Data struct {
+		TTL       int64 `json:"ttl"`
+		Renewable bool  `json:"renewable"`
+	} `json:"data"`
+}
+
+type appRoleLoginRequest struct {
+	RoleID   string `json:"role_id"`
+	SecretID string `json:"secret_id"`
+}
+
+type renewRequest struct {
+	LeaseID   string `json:"lease_id,omitempty"`
+	Increment int64  `json:"increment,omitempty"`
+}
+
+type leaseRenewResponse struct {
+	LeaseID       string `json:"lease_id"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+// Login - authenticate in vault by AppRole credentials or check passed token by lookup-self call.
+// Login will be called by first Get call, but can be called on application start for fail-fast behaviour...
+func (p *provider) Login(ctx context.Context) error {
+	p.loginMu.Lock()
+	defer p.loginMu.Unlock()
+
+	return p.login(ctx)
+}
+
+// relogin - login again after rejection of stale token. Concurrent calls with same stale token
+// are serialized by login mutex - only first call logs in, next calls receive already refreshed token...
+func (p *provider) relogin(ctx context.Context, staleToken string) (string, error) {
+	p.loginMu.Lock()
+	defer p.loginMu.Unlock()
+
+	token, _, _ := p.getToken()
+	if token != "" && token != staleToken {
+		return token, nil
+	}
+
+	err := p.login(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	token, _, _ = p.getToken()
+
+	return token, nil
+}
+
+func (p *provider) login(ctx context.Context) error {
+	switch {
+	case p.options.AppRole != nil:
+		return p.loginByAppRole(ctx)
+	case p.options.Token != "":
+		return p.lookupToken(ctx)
+	default:
+		return ErrAuthMethodIsNotDefined
+	}
+}
+
+func (p *provider) loginByAppRole(ctx context.Context) error {
+	authMount := p.options.AppRole.Mount
+	if authMount == "" {
+		authMount = defaultAppRoleMount
+	}
+
+	response := authResponse{Auth: nil}
+
+	err := p.doRequest(ctx, http.MethodPost, "auth/"+authMount+"/login", "", &appRoleLoginRequest{
+		RoleID:   p.options.AppRole.RoleID,
+		SecretID: p.options.AppRole.SecretID,
+	}, &response)
+	if err != nil {
+		return err
+	}
+
+	if response.Auth == nil || response.Auth.ClientToken == "" {
+		return ErrEmptyAuthResponse
+	}
+
+	p.setToken(response.Auth.ClientToken, response.Auth.LeaseDuration, response.Auth.Renewable)
+
+	return nil
+}
+
+func (p *provider) lookupToken(ctx context.Context) error {
+	response := tokenLookupResponse{}
+
+	err := p.doRequest(ctx, http.MethodGet, "auth/token/lookup-self", p.options.Token, nil, &response)
+	if err != nil {
+		return err
+	}
+
+	p.setToken(p.options.Token, response.Data.TTL, response.Data.Renewable)
+
+	return nil
+}
+
+// RenewToken - renew lease of current vault token. Token TTL will be extended by TokenRenewIncrement option...
+func (p *provider) RenewToken(ctx context.Context) error {
+	token, err := p.ensureToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	response := authResponse{Auth: nil}
+
+	err = p.doRequest(ctx, http.MethodPost, "auth/token/renew-self", token, &renewRequest{
+		LeaseID:   "",
+		Increment: int64(p.options.TokenRenewIncrement.Seconds()),
+	}, &response)
+	if err != nil {
+		return err
+	}
+
+	if response.Auth == nil {
+		return nil
+	}
+
+	p.setToken(token, response.Auth.LeaseDuration, response.Auth.Renewable)
+
+	return nil
+}
+
+// RenewLease - renew lease of secret, returns new expiration time of lease...
+func (p *provider) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (time.Time, error) {
+	token, err := p.ensureToken(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	response := leaseRenewResponse{}
+
+	err = p.doRequest(ctx, http.MethodPut, "sys/leases/renew", token, &renewRequest{
+		LeaseID:   leaseID,
+		Increment: int64(increment.Seconds()),
+	}, &response)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Now().Add(time.Duration(response.LeaseDuration) * time.Second), nil
+}
+
+// Run - background renewal of vault token. Token renewed after 2/3 of its TTL, on renewal failure
+// provider logs in again by AppRole credentials. Function blocks until context cancellation or
+// until token can't be renewed, must be called in separate goroutine...
+func (p *provider) Run(ctx context.Context) error {
+	_, err := p.ensureToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		_, expiresAt, isRenewable := p.getToken()
+		if expiresAt.IsZero() || (!isRenewable && p.options.AppRole == nil) {
+			// token never expires or can't be renewed
+			return nil
+		}
+
+		delay := max(time.Until(expiresAt)*2/3, minRenewDelay)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil
+		case <-timer.C:
+		}
+
+		err = p.renew(ctx, isRenewable)
+		if err != nil && ctx.Err() == nil && time.Now().After(expiresAt) {
+			return err
+		}
+	}
+}
+
+func (p *provider) renew(ctx context.Context, isRenewable bool) error {
+	if isRenewable {
+		err := p.RenewToken(ctx)
+		if err == nil || p.options.AppRole == nil {
+			return err
+		}
+	}
+
+	return p.Login(ctx)
+}
+
+func (p *provider) ensureToken(ctx context.Context) (string, error) {
+	token, _, _ := p.getToken()
+	if token != "" {
+		return token, nil
+	}
+
+	return p.relogin(ctx, "")
+}
+
+func (p *provider) getToken() (string, time.Time, bool) {
+	p.tokenMu.RLock()
+	defer p.tokenMu.RUnlock()
+
+	return p.token, p.tokenExpiresAt, p.isTokenRenewable
+}
+
+func (p *provider) setToken(token string, ttlSeconds int64, isRenewable bool) {
+	p.tokenMu.Lock()
+	defer p.tokenMu.Unlock()
+
+	p.token = token
+	p.isTokenRenewable = isRenewable
+	p.tokenExpiresAt = time.Time{}
+
+	if ttlSeconds > 0 {
+		p.tokenExpiresAt = time.Now().Add(time.Duration(ttlSeconds) * time.Second)
+	}
+}
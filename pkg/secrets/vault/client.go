@@ -0,0 +1,148 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiPathPrefix   = "/v1/"
+	tokenHeader     = "X-Vault-Token"
+	namespaceHeader = "X-Vault-Namespace"
+
+	// maxResponseSize - limit of vault response body size...
+	maxResponseSize = 1 << 20
+)
+
+var errResourceNotFound = errors.New("vault resource not found")
+
+// ResponseError - error response of vault HTTP API...
+type ResponseError struct {
+	Errors     []string
+	StatusCode int
+}
+
+func (e *ResponseError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("vault response status %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("vault response status %d: %s", e.StatusCode, strings.Join(e.Errors, ", "))
+}
+
+// IsPermissionDenied - check that vault rejected request by token permissions or expired token...
+func (e *ResponseError) IsPermissionDenied() bool {
+	return e.StatusCode == http.StatusForbidden
+}
+
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// doRequest - call vault HTTP API. Response with 404 status returned as errResourceNotFound,
+// responses with other non-2xx statuses - as ResponseError...
+func (p *provider) doRequest(ctx context.Context,
+	method, apiPath, token string,
+	requestBody, responseBody interface{},
+) error {
+	var bodyReader io.Reader
+
+	if requestBody != nil {
+		rawBody, err := json.Marshal(requestBody)
+		if err != nil {
+			return err //nolint:wrapcheck // it's ok, json error
+		}
+
+		bodyReader = bytes.NewReader(rawBody)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method,
+		strings.TrimRight(p.options.Address, "/")+apiPathPrefix+apiPath, bodyReader)
+	if err != nil {
+		return err //nolint:wrapcheck // it's ok, request building error
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	if token != "" {
+		request.Header.Set(tokenHeader, token)
+	}
+
+	if p.options.Namespace != "" {
+		request.Header.Set(namespaceHeader, p.options.Namespace)
+	}
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return err //nolint:wrapcheck // it's ok, transport error
+	}
+
+	defer response.Body.Close()
+
+	rawResponse, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return err //nolint:wrapcheck // it's ok, transport error
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return errResourceNotFound
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		responseErr := &ResponseError{
+			Errors:     nil,
+			StatusCode: response.StatusCode,
+		}
+
+		errResponse := errorResponse{Errors: nil}
+		if json.Unmarshal(rawResponse, &errResponse) == nil {
+			responseErr.Errors = errResponse.Errors
+		}
+
+		return responseErr
+	}
+
+	if responseBody == nil || len(rawResponse) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(rawResponse, responseBody) //nolint:wrapcheck // it's ok, json error
+}
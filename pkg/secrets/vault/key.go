@@ -0,0 +1,117 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
+)
+
+var ErrInvalidSecretKey = errors.New("invalid vault secret key")
+
+// versionPrefix - optional prefix of secret version, e.g. "v3"...
+const versionPrefix = "v"
+
+// secretRef - location of secret value in KV v2 secrets engine...
+type secretRef struct {
+	mount   string
+	path    string
+	field   string
+	version uint64
+}
+
+// parseSecretRef - parse secret key in format [vault:][mount/]path[#field][@version], e.g.:
+//   - DB_PASSWORD - field "value" of <Mount>/<BasePath>/DB_PASSWORD secret, latest version
+//   - kv/billing/db#password - field "password" of billing/db secret in kv mount, latest version
+//   - kv/billing/db#password@3 or kv/billing/db#password@v3 - same field of third version of secret
+//
+// Keys without "/" are relative to mount and base path of provider options. In other keys
+// first path segment is a mount of KV v2 secrets engine. Key can be passed by secret_name tag...
+func parseSecretRef(key string, options *Options) (*secretRef, error) {
+	reference, err := secrets.ParseReference(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSecretKey, err)
+	}
+
+	return newSecretRef(reference, options)
+}
+
+// newSecretRef - location of secret by parsed secret reference. Reference scheme must be empty or "vault",
+// default value of reference is ignored...
+func newSecretRef(reference secrets.Reference, options *Options) (*secretRef, error) {
+	ref := &secretRef{
+		mount:   options.Mount,
+		path:    "",
+		field:   options.DefaultField,
+		version: 0,
+	}
+
+	if reference.Scheme != "" && reference.Scheme != sourceName {
+		return nil, fmt.Errorf("%w: wrong scheme: %s", ErrInvalidSecretKey, reference.Key())
+	}
+
+	if reference.Version != "" {
+		version, err := strconv.ParseUint(strings.TrimPrefix(reference.Version, versionPrefix), 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("%w: wrong version: %s", ErrInvalidSecretKey, reference.Key())
+		}
+
+		ref.version = version
+	}
+
+	if reference.Field != "" {
+		ref.field = reference.Field
+	}
+
+	rawPath := strings.Trim(reference.Path, "/")
+	if rawPath == "" {
+		return nil, fmt.Errorf("%w: empty path: %s", ErrInvalidSecretKey, reference.Key())
+	}
+
+	mount, secretPath, hasMount := strings.Cut(rawPath, "/")
+	if !hasMount {
+		ref.path = strings.Trim(path.Join(options.BasePath, rawPath), "/")
+
+		return ref, nil
+	}
+
+	ref.mount = mount
+	ref.path = secretPath
+
+	return ref, nil
+}
@@ -0,0 +1,295 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
+)
+
+var ErrAddressIsNotDefined = errors.New("vault address is not defined")
+
+const (
+	sourceName = "vault"
+
+	defaultMount        = "secret"
+	defaultField        = "value"
+	defaultHTTPTimeout  = 10 * time.Second
+	defaultRenewPeriod  = time.Hour
+	kvDataPathSeparator = "/data/"
+
+	MetadataMount       = "mount"
+	MetadataPath        = "path"
+	MetadataField       = "field"
+	MetadataCreatedTime = "created_time"
+)
+
+var _ secrets.SecretProvider = (*provider)(nil)
+
+// Options - options of vault KV v2 secret provider...
+type Options struct {
+	// HTTPClient - client of vault HTTP API, client with 10s timeout used by default...
+	HTTPClient *http.Client
+	// AppRole - credentials of AppRole auth method. AppRole auth has priority over Token...
+	AppRole *AppRoleAuth
+	// Address - vault address, e.g. https://vault.example.com:8200
+	Address string
+	// Namespace - vault enterprise namespace, optional...
+	Namespace string
+	// Token - static vault token...
+	Token string
+	// Mount - mount of KV v2 secrets engine for keys without mount, "secret" by default...
+	Mount string
+	// BasePath - prefix of secret path for keys without mount, e.g. application name...
+	BasePath string
+	// DefaultField - field of secret data for keys without field, "value" by default...
+	DefaultField string
+	// TokenRenewIncrement - requested TTL extension of token on renewal, 1 hour by default...
+	TokenRenewIncrement time.Duration
+	// WorkersCount - count of concurrent requests of GetMany function...
+	WorkersCount int
+}
+
+type kvReadResponse struct {
+	Data *struct {
+		Data     map[string]json.RawMessage `json:"data"`
+		Metadata struct {
+			CreatedTime  string `json:"created_time"`
+			DeletionTime string `json:"deletion_time"`
+			Version      uint64 `json:"version"`
+			Destroyed    bool   `json:"destroyed"`
+		} `json:"metadata"`
+	} `json:"data"`
+	LeaseID       string `json:"lease_id"`
+	LeaseDuration int64  `json:"lease_duration"`
+}
+
+// provider - secret provider of HashiCorp Vault KV v2 secrets engine...
+type provider struct {
+	httpClient *http.Client
+	options    Options
+
+	loginMu sync.Mutex
+
+	tokenMu          sync.RWMutex
+	token            string
+	tokenExpiresAt   time.Time
+	isTokenRenewable bool
+}
+
+// Get - read secret field by key in format [mount/]path[#field][@version]. Absent secret, field,
+// deleted or destroyed version of secret returned as secrets.ErrSecretNotFound error...
+func (p *provider) Get(ctx context.Context, key string) (secrets.Secret, error) {
+	ref, err := parseSecretRef(key, &p.options)
+	if err != nil {
+		return secrets.Secret{}, err
+	}
+
+	return p.getSecret(ctx, key, ref)
+}
+
+// GetByReference - read secret field by parsed secret reference, e.g. vault:wallet/hot#password@v3.
+// Path of reference is a path of Get function key, default value of reference is ignored...
+func (p *provider) GetByReference(ctx context.Context, reference secrets.Reference) (secrets.Secret, error) {
+	ref, err := newSecretRef(reference, &p.options)
+	if err != nil {
+		return secrets.Secret{}, err
+	}
+
+	return p.getSecret(ctx, reference.Key(), ref)
+}
+
+func (p *provider) getSecret(ctx context.Context, key string, ref *secretRef) (secrets.Secret, error) {
+	token, err := p.ensureToken(ctx)
+	if err != nil {
+		return secrets.Secret{}, err
+	}
+
+	response, err := p.readSecret(ctx, ref, token)
+
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) && responseErr.IsPermissionDenied() && p.options.AppRole != nil {
+		// token could be expired or revoked - login again and retry
+		token, err = p.relogin(ctx, token)
+		if err != nil {
+			return secrets.Secret{}, err
+		}
+
+		response, err = p.readSecret(ctx, ref, token)
+	}
+
+	if err != nil {
+		return secrets.Secret{}, err
+	}
+
+	return newSecret(key, ref, response)
+}
+
+// GetMany - read secrets by concurrent requests...
+func (p *provider) GetMany(ctx context.Context, keysList []string) (map[string]secrets.Secret, error) {
+	return secrets.GetManyConcurrently(ctx, p, keysList, p.options.WorkersCount)
+}
+
+// GetByName - GetByName interface of secret manager, errors of vault are treated as absence of secret.
+// Use Get function for error handling...
+func (p *provider) GetByName(keyName string) (string, bool) {
+	secret, err := p.Get(context.Background(), keyName)
+	if err != nil {
+		return "", false
+	}
+
+	return secret.Value, true
+}
+
+// GetSourceName returns name of secret provider...
+func (p *provider) GetSourceName() string {
+	return sourceName
+}
+
+func (p *provider) readSecret(ctx context.Context, ref *secretRef, token string) (*kvReadResponse, error) {
+	apiPath := ref.mount + kvDataPathSeparator + ref.path
+	if ref.version != 0 {
+		apiPath += "?" + url.Values{"version": []string{strconv.FormatUint(ref.version, 10)}}.Encode()
+	}
+
+	response := &kvReadResponse{
+		Data:          nil,
+		LeaseID:       "",
+		LeaseDuration: 0,
+	}
+
+	err := p.doRequest(ctx, http.MethodGet, apiPath, token, nil, response)
+	if errors.Is(err, errResourceNotFound) {
+		return nil, fmt.Errorf("%w: %s/%s", secrets.ErrSecretNotFound, ref.mount, ref.path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func newSecret(key string, ref *secretRef, response *kvReadResponse) (secrets.Secret, error) {
+	if response.Data == nil || response.Data.Data == nil ||
+		response.Data.Metadata.DeletionTime != "" || response.Data.Metadata.Destroyed {
+		return secrets.Secret{}, fmt.Errorf("%w: %s/%s", secrets.ErrSecretNotFound, ref.mount, ref.path)
+	}
+
+	rawValue, isExists := response.Data.Data[ref.field]
+	if !isExists {
+		return secrets.Secret{}, fmt.Errorf("%w: %s/%s#%s", secrets.ErrSecretNotFound,
+			ref.mount, ref.path, ref.field)
+	}
+
+	secret := secrets.Secret{
+		ExpiresAt: time.Time{},
+		Metadata: map[string]string{
+			MetadataMount:       ref.mount,
+			MetadataPath:        ref.path,
+			MetadataField:       ref.field,
+			MetadataCreatedTime: response.Data.Metadata.CreatedTime,
+		},
+		Key:     key,
+		Value:   string(rawValue),
+		Version: strconv.FormatUint(response.Data.Metadata.Version, 10),
+		LeaseID: response.LeaseID,
+	}
+
+	// non-string values, e.g. numbers or objects, returned as raw JSON
+	var stringValue string
+	if json.Unmarshal(rawValue, &stringValue) == nil {
+		secret.Value = stringValue
+	}
+
+	if response.LeaseDuration > 0 {
+		secret.ExpiresAt = time.Now().Add(time.Duration(response.LeaseDuration) * time.Second)
+	}
+
+	return secret, nil
+}
+
+// NewProvider - create secret provider of HashiCorp Vault KV v2 secrets engine, e.g.:
+//
+//	vaultSecrets, err := vault.NewProvider(vault.Options{
+//		Address: "https://vault:8200", Mount: "kv", BasePath: "billing",
+//		AppRole: &vault.AppRoleAuth{RoleID: roleID, SecretID: secretID}})
+//	go vaultSecrets.Run(ctx)
+//	err = config.NewConfigManager(nil).PrepareTo(appCfg).With(vaultSecrets).Do(ctx)
+//
+// Secret key of config field can be passed by secret_name tag: `secret:"true" secret_name:"kv/billing/db#password"`...
+func NewProvider(options Options) (*provider, error) {
+	if options.Address == "" {
+		return nil, ErrAddressIsNotDefined
+	}
+
+	if options.AppRole == nil && options.Token == "" {
+		return nil, ErrAuthMethodIsNotDefined
+	}
+
+	if options.Mount == "" {
+		options.Mount = defaultMount
+	}
+
+	if options.DefaultField == "" {
+		options.DefaultField = defaultField
+	}
+
+	if options.TokenRenewIncrement <= 0 {
+		options.TokenRenewIncrement = defaultRenewPeriod
+	}
+
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout} //nolint:exhaustruct // it's ok, default client
+	}
+
+	return &provider{
+		httpClient:       httpClient,
+		options:          options,
+		loginMu:          sync.Mutex{},
+		tokenMu:          sync.RWMutex{},
+		token:            "",
+		tokenExpiresAt:   time.Time{},
+		isTokenRenewable: false,
+	}, nil
+}
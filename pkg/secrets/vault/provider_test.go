@@ -0,0 +1,323 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/config"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets"
+	"github.com/crypto-bundle/bc-wallet-common-lib-config/pkg/secrets/vault/vaulttest"
+)
+
+func TestParseSecretRef(t *testing.T) {
+	options := &Options{Mount: "secret", BasePath: "billing", DefaultField: "value"}
+
+	casesList := []struct {
+		key      string
+		expected secretRef
+	}{
+		{"DB_PASSWORD", secretRef{mount: "secret", path: "billing/DB_PASSWORD", field: "value", version: 0}},
+		{"kv/team/db#password", secretRef{mount: "kv", path: "team/db", field: "password", version: 0}},
+		{"kv/team/db#password@3", secretRef{mount: "kv", path: "team/db", field: "password", version: 3}},
+		{"kv/team/db@2", secretRef{mount: "kv", path: "team/db", field: "value", version: 2}},
+		{"vault:kv/team/db#password@v3", secretRef{mount: "kv", path: "team/db", field: "password", version: 3}},
+	}
+
+	for _, testCase := range casesList {
+		ref, err := parseSecretRef(testCase.key, options)
+		if err != nil {
+			t.Errorf("%s: %s", testCase.key, err)
+			continue
+		}
+
+		if *ref != testCase.expected {
+			t.Errorf("%s: not equal secret ref: %+v", testCase.key, *ref)
+		}
+	}
+
+	for _, key := range []string{"", "kv/db#", "kv/db@x1", "kv/db@0", "#field", "aws:kv/db"} {
+		_, err := parseSecretRef(key, options)
+		if !errors.Is(err, ErrInvalidSecretKey) {
+			t.Errorf("%s: expected invalid key error: %v", key, err)
+		}
+	}
+}
+
+func TestProviderTokenAuth(t *testing.T) {
+	vaultSrv := vaulttest.NewServer()
+	defer vaultSrv.Close()
+
+	vaultSrv.PutSecret("secret", "billing/DB_PASSWORD", map[string]interface{}{"value": "first"})
+	vaultSrv.PutSecret("secret", "billing/DB_PASSWORD", map[string]interface{}{"value": "second"})
+	vaultSrv.PutSecret("kv", "team/db", map[string]interface{}{"user": "admin", "port": 5432})
+
+	provider, err := NewProvider(Options{
+		Address:  vaultSrv.URL(),
+		Token:    vaulttest.RootToken,
+		BasePath: "billing",
+	})
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	ctx := context.Background()
+
+	secret, err := provider.Get(ctx, "DB_PASSWORD")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if secret.Value != "second" || secret.Version != "2" || secret.Metadata[MetadataPath] != "billing/DB_PASSWORD" {
+		t.Errorf("not equal latest version of secret: %+v", secret)
+	}
+
+	secret, err = provider.Get(ctx, "DB_PASSWORD@1")
+	if err != nil || secret.Value != "first" {
+		t.Errorf("not equal first version of secret: %+v, %v", secret, err)
+	}
+
+	secret, err = provider.Get(ctx, "kv/team/db#port")
+	if err != nil || secret.Value != "5432" {
+		t.Errorf("non-string value must be returned as raw JSON: %+v, %v", secret, err)
+	}
+
+	vaultSrv.DeleteSecretVersion("secret", "billing/DB_PASSWORD", 2)
+
+	for _, key := range []string{"DB_PASSWORD", "DB_PASSWORD@3", "kv/team/db#password", "kv/unknown#user"} {
+		_, err = provider.Get(ctx, key)
+		if !errors.Is(err, secrets.ErrSecretNotFound) {
+			t.Errorf("%s: expected not found error: %v", key, err)
+		}
+	}
+
+	secretsList, err := provider.GetMany(ctx, []string{"DB_PASSWORD@1", "kv/team/db#user", "kv/unknown"})
+	if err != nil || len(secretsList) != 2 || secretsList["kv/team/db#user"].Value != "admin" {
+		t.Errorf("not equal secrets list: %+v, %v", secretsList, err)
+	}
+
+	vaultSrv.RevokeToken(vaulttest.RootToken)
+
+	var responseErr *ResponseError
+
+	_, err = provider.Get(ctx, "DB_PASSWORD@1")
+	if !errors.As(err, &responseErr) || !responseErr.IsPermissionDenied() {
+		t.Errorf("expected permission denied error: %v", err)
+	}
+}
+
+func TestProviderAppRoleAuthAndLeases(t *testing.T) {
+	vaultSrv := vaulttest.NewServer()
+	defer vaultSrv.Close()
+
+	vaultSrv.AddAppRole("role-id", "secret-id", time.Minute)
+	vaultSrv.PutSecret("secret", "api", map[string]interface{}{"token": "api-token"})
+	vaultSrv.SetSecretLease("secret", "api", time.Minute)
+
+	provider, err := NewProvider(Options{
+		Address: vaultSrv.URL(),
+		AppRole: &AppRoleAuth{RoleID: "role-id", SecretID: "secret-id", Mount: ""},
+	})
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	ctx := context.Background()
+
+	secret, err := provider.Get(ctx, "secret/api#token")
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if secret.Value != "api-token" || !secret.HasLease() || secret.LeaseID == "" {
+		t.Errorf("not equal secret with lease: %+v", secret)
+	}
+
+	expiresAt, err := provider.RenewLease(ctx, secret.LeaseID, time.Hour)
+	if err != nil || time.Until(expiresAt) < time.Minute*59 {
+		t.Errorf("lease must be renewed: %s, %v", expiresAt, err)
+	}
+
+	token, tokenExpiresAt, isRenewable := provider.getToken()
+	if !isRenewable || tokenExpiresAt.IsZero() {
+		t.Errorf("AppRole token must be renewable and must have TTL")
+	}
+
+	err = provider.RenewToken(ctx)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	_, renewedExpiresAt, _ := provider.getToken()
+	if !renewedExpiresAt.After(tokenExpiresAt) {
+		t.Errorf("token TTL must be extended by renewal")
+	}
+
+	// revoked token must be replaced by new login
+	vaultSrv.RevokeToken(token)
+
+	_, err = provider.Get(ctx, "secret/api#token")
+	if err != nil {
+		t.Errorf("provider must login again after token revocation: %s", err)
+	}
+
+	newToken, _, _ := provider.getToken()
+	if newToken == token {
+		t.Errorf("token must be replaced")
+	}
+
+	_, err = NewProvider(Options{Address: vaultSrv.URL()})
+	if !errors.Is(err, ErrAuthMethodIsNotDefined) {
+		t.Errorf("expected auth method error: %v", err)
+	}
+}
+
+func TestProviderConcurrentRelogin(t *testing.T) {
+	vaultSrv := vaulttest.NewServer()
+	defer vaultSrv.Close()
+
+	vaultSrv.AddAppRole("role-id", "secret-id", time.Minute)
+	vaultSrv.PutSecret("secret", "api", map[string]interface{}{"token": "api-token"})
+
+	provider, err := NewProvider(Options{
+		Address: vaultSrv.URL(),
+		AppRole: &AppRoleAuth{RoleID: "role-id", SecretID: "secret-id", Mount: ""},
+	})
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	ctx := context.Background()
+
+	// renewal of token must login first, if provider has no token
+	err = provider.RenewToken(ctx)
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	token, _, _ := provider.getToken()
+	vaultSrv.RevokeToken(token)
+
+	const callsCount = 16
+
+	var waitGroup sync.WaitGroup
+
+	waitGroup.Add(callsCount)
+
+	for range callsCount {
+		go func() {
+			defer waitGroup.Done()
+
+			_, getErr := provider.Get(ctx, "secret/api#token")
+			if getErr != nil {
+				t.Errorf("%s", getErr)
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+
+	if vaultSrv.LoginsCount() != 2 {
+		t.Errorf("rejected token must be replaced by one login: %d logins", vaultSrv.LoginsCount())
+	}
+}
+
+func TestProviderEmptyAuthResponse(t *testing.T) {
+	vaultSrv := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = writer.Write([]byte(`{"auth":null}`))
+	}))
+	defer vaultSrv.Close()
+
+	provider, err := NewProvider(Options{
+		Address: vaultSrv.URL,
+		AppRole: &AppRoleAuth{RoleID: "role-id", SecretID: "secret-id", Mount: ""},
+	})
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	err = provider.Login(context.Background())
+	if !errors.Is(err, ErrEmptyAuthResponse) {
+		t.Errorf("expected empty auth response error: %v", err)
+	}
+}
+
+type vaultConfig struct {
+	DBPassword string `envconfig:"VAULT_TEST_DB_PASSWORD" secret:"true" secret_name:"kv/billing/db#password"`
+	APIToken   string `envconfig:"VAULT_TEST_API_TOKEN" secret:"true"`
+}
+
+func TestProviderWithConfigManager(t *testing.T) {
+	vaultSrv := vaulttest.NewServer()
+	defer vaultSrv.Close()
+
+	vaultSrv.PutSecret("kv", "billing/db", map[string]interface{}{"password": "qwerty"})
+	vaultSrv.PutSecret("secret", "billing/VAULT_TEST_API_TOKEN", map[string]interface{}{"value": "api-token"})
+
+	provider, err := NewProvider(Options{
+		Address:  vaultSrv.URL(),
+		Token:    vaulttest.RootToken,
+		BasePath: "billing",
+	})
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	cfg := &vaultConfig{}
+
+	err = config.NewConfigManager(nil).PrepareTo(cfg).With(provider).Do(context.Background())
+	if err != nil {
+		t.Errorf("%s", err)
+		return
+	}
+
+	if cfg.DBPassword != "qwerty" || cfg.APIToken != "api-token" {
+		t.Errorf("not equal config values: %+v", cfg)
+	}
+}
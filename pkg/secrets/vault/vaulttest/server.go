@@ -0,0 +1,499 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package vaulttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// RootToken - non-expiring token with access to all secrets...
+	RootToken = "vaulttest-root-token"
+
+	apiPathPrefix   = "/v1/"
+	tokenHeader     = "X-Vault-Token"
+	kvDataSeparator = "/data/"
+)
+
+type tokenEntry struct {
+	expiresAt   time.Time
+	ttl         time.Duration
+	isRenewable bool
+}
+
+type appRoleEntry struct {
+	secretID string
+	tokenTTL time.Duration
+}
+
+type secretVersion struct {
+	createdAt time.Time
+	deletedAt time.Time
+	data      map[string]interface{}
+}
+
+type kvSecret struct {
+	versionsList []*secretVersion
+	leaseTTL     time.Duration
+}
+
+type errorsBody struct {
+	Errors []string `json:"errors"`
+}
+
+// fakeServer - in-process stand-in of HashiCorp Vault HTTP API for integration tests. Supported API:
+//   - KV v2 secrets engine: read and write of secret data with versions, on any mount
+//   - token auth: lookup-self and renew-self
+//   - AppRole auth: login on any auth mount
+//   - sys/leases/renew - renewal of secret leases
+type fakeServer struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	tokens   map[string]*tokenEntry
+	appRoles map[string]*appRoleEntry
+	secrets  map[string]*kvSecret
+	leases   map[string]time.Duration
+
+	requestsCount atomic.Int64
+	tokensCount   atomic.Int64
+}
+
+// URL returns address of fake vault server...
+func (s *fakeServer) URL() string {
+	return s.server.URL
+}
+
+// Close - shutdown fake vault server...
+func (s *fakeServer) Close() {
+	s.server.Close()
+}
+
+// RequestsCount returns count of handled requests...
+func (s *fakeServer) RequestsCount() int64 {
+	return s.requestsCount.Load()
+}
+
+// LoginsCount returns count of tokens, issued by AppRole logins...
+func (s *fakeServer) LoginsCount() int64 {
+	return s.tokensCount.Load()
+}
+
+// AddToken - register token with TTL. Zero TTL - token never expires...
+func (s *fakeServer) AddToken(token string, ttl time.Duration, isRenewable bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[token] = newTokenEntry(ttl, isRenewable)
+}
+
+// RevokeToken - revoke token, next requests with token will be rejected with 403 status...
+func (s *fakeServer) RevokeToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, token)
+}
+
+// AddAppRole - register AppRole credentials. Login by credentials issues renewable token with passed TTL...
+func (s *fakeServer) AddAppRole(roleID, secretID string, tokenTTL time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.appRoles[roleID] = &appRoleEntry{
+		secretID: secretID,
+		tokenTTL: tokenTTL,
+	}
+}
+
+// PutSecret - write new version of KV v2 secret, returns number of version...
+func (s *fakeServer) PutSecret(mount, path string, data map[string]interface{}) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.putSecret(mount+kvDataSeparator+strings.Trim(path, "/"), data)
+}
+
+// DeleteSecretVersion - soft delete of secret version, like DELETE call of KV v2 engine...
+func (s *fakeServer) DeleteSecretVersion(mount, path string, version uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret, isExists := s.secrets[mount+kvDataSeparator+strings.Trim(path, "/")]
+	if !isExists || version == 0 || version > uint64(len(secret.versionsList)) {
+		return
+	}
+
+	secret.versionsList[version-1].deletedAt = time.Now()
+}
+
+// SetSecretLease - set lease duration of secret, read responses of secret will contain lease ID...
+func (s *fakeServer) SetSecretLease(mount, path string, leaseTTL time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret, isExists := s.secrets[mount+kvDataSeparator+strings.Trim(path, "/")]
+	if isExists {
+		secret.leaseTTL = leaseTTL
+	}
+}
+
+func (s *fakeServer) putSecret(secretPath string, data map[string]interface{}) uint64 {
+	secret, isExists := s.secrets[secretPath]
+	if !isExists {
+		secret = &kvSecret{
+			versionsList: make([]*secretVersion, 0),
+			leaseTTL:     0,
+		}
+		s.secrets[secretPath] = secret
+	}
+
+	secret.versionsList = append(secret.versionsList, &secretVersion{
+		createdAt: time.Now(),
+		deletedAt: time.Time{},
+		data:      data,
+	})
+
+	return uint64(len(secret.versionsList))
+}
+
+func (s *fakeServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	s.requestsCount.Add(1)
+
+	apiPath := strings.TrimPrefix(request.URL.Path, apiPathPrefix)
+
+	switch {
+	case strings.HasPrefix(apiPath, "auth/") && strings.HasSuffix(apiPath, "/login"):
+		s.handleAppRoleLogin(writer, request)
+	case apiPath == "auth/token/lookup-self":
+		s.withToken(writer, request, s.handleLookupSelf)
+	case apiPath == "auth/token/renew-self":
+		s.withToken(writer, request, s.handleRenewSelf)
+	case apiPath == "sys/leases/renew":
+		s.withToken(writer, request, s.handleLeaseRenew)
+	case strings.Contains(apiPath, kvDataSeparator):
+		s.withToken(writer, request, s.handleKVData)
+	default:
+		writeErrors(writer, http.StatusNotFound)
+	}
+}
+
+func (s *fakeServer) withToken(writer http.ResponseWriter, request *http.Request,
+	handler func(writer http.ResponseWriter, request *http.Request, token string),
+) {
+	token := request.Header.Get(tokenHeader)
+
+	s.mu.Lock()
+	entry, isExists := s.tokens[token]
+	isValid := isExists && (entry.expiresAt.IsZero() || time.Now().Before(entry.expiresAt))
+	s.mu.Unlock()
+
+	if !isValid {
+		writeErrors(writer, http.StatusForbidden, "permission denied")
+
+		return
+	}
+
+	handler(writer, request, token)
+}
+
+func (s *fakeServer) handleAppRoleLogin(writer http.ResponseWriter, request *http.Request) {
+	loginRequest := struct {
+		RoleID   string `json:"role_id"`
+		SecretID string `json:"secret_id"`
+	}{}
+
+	err := json.NewDecoder(request.Body).Decode(&loginRequest)
+	if err != nil {
+		writeErrors(writer, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	appRole, isExists := s.appRoles[loginRequest.RoleID]
+	if !isExists || appRole.secretID != loginRequest.SecretID {
+		writeErrors(writer, http.StatusBadRequest, "invalid role or secret ID")
+
+		return
+	}
+
+	token := "s.vaulttest." + strconv.FormatInt(s.tokensCount.Add(1), 10)
+	s.tokens[token] = newTokenEntry(appRole.tokenTTL, true)
+
+	writeJSON(writer, map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token":   token,
+			"lease_duration": int64(appRole.tokenTTL.Seconds()),
+			"renewable":      true,
+		},
+	})
+}
+
+func (s *fakeServer) handleLookupSelf(writer http.ResponseWriter, _ *http.Request, token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.tokens[token]
+
+	ttl := int64(0)
+	if !entry.expiresAt.IsZero() {
+		ttl = int64(time.Until(entry.expiresAt).Seconds())
+	}
+
+	writeJSON(writer, map[string]interface{}{
+		"data": map[string]interface{}{
+			"ttl":       ttl,
+			"renewable": entry.isRenewable,
+		},
+	})
+}
+
+func (s *fakeServer) handleRenewSelf(writer http.ResponseWriter, request *http.Request, token string) {
+	renewRequest := struct {
+		Increment int64 `json:"increment"`
+	}{}
+
+	_ = json.NewDecoder(request.Body).Decode(&renewRequest)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.tokens[token]
+	if !entry.isRenewable {
+		writeErrors(writer, http.StatusBadRequest, "lease is not renewable")
+
+		return
+	}
+
+	ttl := entry.ttl
+	if renewRequest.Increment > 0 {
+		ttl = time.Duration(renewRequest.Increment) * time.Second
+	}
+
+	entry.expiresAt = time.Now().Add(ttl)
+
+	writeJSON(writer, map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token":   token,
+			"lease_duration": int64(ttl.Seconds()),
+			"renewable":      true,
+		},
+	})
+}
+
+func (s *fakeServer) handleLeaseRenew(writer http.ResponseWriter, request *http.Request, _ string) {
+	renewRequest := struct {
+		LeaseID   string `json:"lease_id"`
+		Increment int64  `json:"increment"`
+	}{}
+
+	_ = json.NewDecoder(request.Body).Decode(&renewRequest)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	leaseTTL, isExists := s.leases[renewRequest.LeaseID]
+	if !isExists {
+		writeErrors(writer, http.StatusBadRequest, "lease not found or lease is not renewable")
+
+		return
+	}
+
+	if renewRequest.Increment > 0 {
+		leaseTTL = time.Duration(renewRequest.Increment) * time.Second
+	}
+
+	writeJSON(writer, map[string]interface{}{
+		"lease_id":       renewRequest.LeaseID,
+		"lease_duration": int64(leaseTTL.Seconds()),
+		"renewable":      true,
+	})
+}
+
+func (s *fakeServer) handleKVData(writer http.ResponseWriter, request *http.Request, _ string) {
+	secretPath := strings.TrimSuffix(strings.TrimPrefix(request.URL.Path, apiPathPrefix), "/")
+
+	switch request.Method {
+	case http.MethodGet:
+		s.readSecret(writer, request, secretPath)
+	case http.MethodPost, http.MethodPut:
+		writeRequest := struct {
+			Data map[string]interface{} `json:"data"`
+		}{}
+
+		err := json.NewDecoder(request.Body).Decode(&writeRequest)
+		if err != nil {
+			writeErrors(writer, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		s.mu.Lock()
+		version := s.putSecret(secretPath, writeRequest.Data)
+		s.mu.Unlock()
+
+		writeJSON(writer, map[string]interface{}{
+			"data": map[string]interface{}{"version": version},
+		})
+	default:
+		writeErrors(writer, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fakeServer) readSecret(writer http.ResponseWriter, request *http.Request, secretPath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret, isExists := s.secrets[secretPath]
+	if !isExists {
+		writeErrors(writer, http.StatusNotFound)
+
+		return
+	}
+
+	version := uint64(len(secret.versionsList))
+
+	rawVersion := request.URL.Query().Get("version")
+	if rawVersion != "" && rawVersion != "0" {
+		parsedVersion, err := strconv.ParseUint(rawVersion, 10, 64)
+		if err != nil {
+			writeErrors(writer, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		version = parsedVersion
+	}
+
+	if version == 0 || version > uint64(len(secret.versionsList)) {
+		writeErrors(writer, http.StatusNotFound)
+
+		return
+	}
+
+	secretVersion := secret.versionsList[version-1]
+	metadata := map[string]interface{}{
+		"created_time":  secretVersion.createdAt.Format(time.RFC3339Nano),
+		"deletion_time": "",
+		"destroyed":     false,
+		"version":       version,
+	}
+
+	if !secretVersion.deletedAt.IsZero() {
+		// KV v2 engine responds with 404 status and metadata for deleted versions
+		metadata["deletion_time"] = secretVersion.deletedAt.Format(time.RFC3339Nano)
+
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(writer).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"data": nil, "metadata": metadata},
+		})
+
+		return
+	}
+
+	leaseID := ""
+	if secret.leaseTTL > 0 {
+		leaseID = secretPath + "/" + strconv.FormatUint(version, 10)
+		s.leases[leaseID] = secret.leaseTTL
+	}
+
+	writeJSON(writer, map[string]interface{}{
+		"lease_id":       leaseID,
+		"lease_duration": int64(secret.leaseTTL.Seconds()),
+		"renewable":      leaseID != "",
+		"data": map[string]interface{}{
+			"data":     secretVersion.data,
+			"metadata": metadata,
+		},
+	})
+}
+
+func newTokenEntry(ttl time.Duration, isRenewable bool) *tokenEntry {
+	entry := &tokenEntry{
+		expiresAt:   time.Time{},
+		ttl:         ttl,
+		isRenewable: isRenewable,
+	}
+
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	return entry
+}
+
+func writeJSON(writer http.ResponseWriter, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(writer).Encode(body)
+}
+
+func writeErrors(writer http.ResponseWriter, statusCode int, errorsList ...string) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	_ = json.NewEncoder(writer).Encode(errorsBody{Errors: append(make([]string, 0), errorsList...)})
+}
+
+// NewServer - start fake vault server with RootToken, e.g.:
+//
+//	vaultSrv := vaulttest.NewServer()
+//	defer vaultSrv.Close()
+//
+//	vaultSrv.PutSecret("secret", "billing/db", map[string]interface{}{"password": "qwerty"})
+//	vaultSecrets, err := vault.NewProvider(vault.Options{Address: vaultSrv.URL(), Token: vaulttest.RootToken})
+func NewServer() *fakeServer {
+	fakeSrv := &fakeServer{
+		server:        nil,
+		mu:            sync.Mutex{},
+		tokens:        map[string]*tokenEntry{RootToken: newTokenEntry(0, false)},
+		appRoles:      make(map[string]*appRoleEntry),
+		secrets:       make(map[string]*kvSecret),
+		leases:        make(map[string]time.Duration),
+		requestsCount: atomic.Int64{},
+		tokensCount:   atomic.Int64{},
+	}
+
+	fakeSrv.server = httptest.NewServer(fakeSrv)
+
+	return fakeSrv
+}